@@ -1,6 +0,0 @@
-package main
-
-type BaseFunction interface {
-	Value
-	Execute(arguments []Value) *RuntimeResult
-}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gomeo/interp"
+)
+
+type Command struct {
+	argument    string
+	description string
+	run         func(repl *Repl, argument string)
+}
+
+var commands map[string]*Command
+
+func init() {
+	commands = map[string]*Command{
+		":help": {"", "show this list of commands", func(repl *Repl, argument string) {
+			writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			for _, name := range commandNames() {
+				command := commands[name]
+				fmt.Fprintf(writer, "%s %s\t%s\n", name, command.argument, command.description)
+			}
+			writer.Flush()
+		}},
+
+		":vars": {"", "list the global variables and their types", func(repl *Repl, argument string) {
+			writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			for _, name := range repl.interpreter.Names() {
+				fmt.Fprintf(writer, "%s\t%s\n", name, interp.TypeName(repl.interpreter.Get(name)))
+			}
+			writer.Flush()
+		}},
+
+		":type": {"expr", "evaluate expr and show the type of its value", func(repl *Repl, argument string) {
+			value, err := repl.interpreter.Run("<stdin>", argument)
+			if err != nil {
+				repl.Print(nil, err)
+				return
+			}
+			fmt.Println(interp.TypeName(value))
+		}},
+
+		":ast": {"expr", "show the syntax tree of expr", func(repl *Repl, argument string) {
+			node, err := interp.Parse("<stdin>", argument)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			fmt.Println(node)
+		}},
+
+		":tokens": {"expr", "show the tokens of expr", func(repl *Repl, argument string) {
+			tokens, err := interp.Tokenize("<stdin>", argument)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			words := make([]string, len(tokens))
+			for i, token := range tokens {
+				words[i] = token.String()
+			}
+			fmt.Println(strings.Join(words, " "))
+		}},
+
+		":load": {"file", "run file in the current session", func(repl *Repl, argument string) {
+			content, err := ioutil.ReadFile(argument)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			repl.Print(repl.interpreter.Run(argument, string(content)))
+		}},
+
+		":reset": {"", "start over with a fresh interpreter", func(repl *Repl, argument string) {
+			repl.Reset()
+		}},
+
+		":time": {"expr", "evaluate expr and show how long it took", func(repl *Repl, argument string) {
+			start := time.Now()
+			value, err := repl.interpreter.Run("<stdin>", argument)
+			elapsed := time.Since(start)
+			if err == nil && value != nil {
+				fmt.Println(value)
+			} else if err != nil {
+				repl.Print(value, err)
+			}
+			fmt.Printf("took %s\n", elapsed)
+		}},
+	}
+}
+
+func commandNames() []string {
+	res := make([]string, 0, len(commands))
+	for name := range commands {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (self *Repl) Command(line string) {
+	name := line
+	argument := ""
+	if index := strings.IndexAny(line, " \t"); index >= 0 {
+		name, argument = line[:index], strings.TrimSpace(line[index:])
+	}
+
+	command := commands[name]
+	if command == nil {
+		fmt.Printf("Unknown command '%s', type :help for a list of commands\n", name)
+		return
+	}
+	if command.argument != "" && argument == "" {
+		fmt.Printf("usage: %s %s\n", name, command.argument)
+		return
+	}
+	command.run(self, argument)
+}
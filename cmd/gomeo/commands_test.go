@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gomeo/interp"
+)
+
+func captureStdout(t *testing.T, run func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	run()
+	writer.Close()
+	output, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		line, want string
+	}{
+		{":type [1]", "list\n"},
+		{":type   1 + 1", "number\n"},
+		{":type", "usage: :type expr\n"},
+		{":tokens 1 + x", "NUMBER:1 PLUS IDENTIFIER:x EOF\n"},
+		{":type x", "number\n"},
+		{":load /missing.gm", "open /missing.gm: no such file or directory\n"},
+		{":nope", "Unknown command ':nope', type :help for a list of commands\n"},
+	}
+	for _, test := range tests {
+		repl := &Repl{interpreter: interp.NewInterpreter(interp.Options{})}
+		if _, err := repl.interpreter.Run("<test>", "var x = 1"); err != nil {
+			t.Fatal(err)
+		}
+		got := captureStdout(t, func() { repl.Command(test.line) })
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", test.line, got, test.want)
+		}
+	}
+}
+
+func TestResetCommand(t *testing.T) {
+	repl := &Repl{interpreter: interp.NewInterpreter(interp.Options{})}
+	if _, err := repl.interpreter.Run("<test>", "var x = 1"); err != nil {
+		t.Fatal(err)
+	}
+	repl.Command(":reset")
+	if repl.interpreter.Get("x") != nil {
+		t.Error(":reset kept the variables of the old session")
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/peterh/liner"
+
+	"gomeo/interp"
+)
+
+const HISTORY_FILE = ".gomeo_history"
+
+type Repl struct {
+	// mutex guards interpreter against the signal handler, which interrupts it from another
+	// goroutine while :reset may replace it.
+	mutex       sync.Mutex
+	interpreter *interp.Interpreter
+	editor      *liner.State
+}
+
+func NewRepl() *Repl {
+	res := &Repl{}
+	res.Reset()
+
+	res.editor = liner.NewLiner()
+	res.editor.SetCtrlCAborts(true)
+	res.editor.SetWordCompleter(res.Complete)
+	res.LoadHistory()
+	return res
+}
+
+func shell() {
+	repl := NewRepl()
+	defer repl.Close()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	defer signal.Stop(c)
+	go func() {
+		for range c {
+			repl.Interrupt()
+		}
+	}()
+
+	repl.Loop()
+}
+
+func (self *Repl) Reset() {
+	interpreter := interp.NewInterpreter(interp.Options{
+		Capabilities: interp.CAPABILITY_ALL,
+	})
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.interpreter = interpreter
+}
+
+func (self *Repl) Interrupt() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.interpreter.Interrupt()
+}
+
+func (self *Repl) Close() {
+	self.SaveHistory()
+	self.editor.Close()
+}
+
+func (self *Repl) Exit(code int) {
+	self.Close()
+	os.Exit(code)
+}
+
+func (self *Repl) Loop() {
+	pending := ""
+	aborted := false
+
+	for {
+		prompt := "gomeo> "
+		if pending != "" {
+			prompt = "  ...> "
+		}
+
+		line, err := self.editor.Prompt(prompt)
+		if err == liner.ErrPromptAborted {
+			if pending != "" {
+				pending = ""
+				continue
+			}
+			if aborted {
+				return
+			}
+			aborted = true
+			fmt.Println("(press Ctrl-C again to exit)")
+			continue
+		} else if err != nil {
+			fmt.Println()
+			return
+		}
+		aborted = false
+		blank := strings.Trim(line, " \t") == ""
+
+		if pending == "" && blank {
+			fmt.Println()
+			continue
+		}
+		if !blank {
+			self.editor.AppendHistory(line)
+		}
+
+		if pending == "" && strings.HasPrefix(strings.TrimLeft(line, " \t"), ":") {
+			self.Command(strings.TrimLeft(line, " \t"))
+			fmt.Println()
+			continue
+		}
+
+		text := line
+		if pending != "" {
+			text = pending + "\n" + line
+		}
+
+		value, err := self.interpreter.Run("<stdin>", text)
+		if syntaxError, ok := err.(*interp.Error); ok && syntaxError.Incomplete() && !blank {
+			pending = text
+			continue
+		}
+		pending = ""
+
+		self.Print(value, err)
+	}
+}
+
+func (self *Repl) Print(value interp.Value, err error) {
+	if exit, ok := err.(*interp.ExitError); ok {
+		self.Exit(exit.Code)
+	} else if err != nil {
+		fmt.Println(err)
+		return
+	} else if value != nil {
+		fmt.Println(value)
+	}
+
+	fmt.Println()
+}
+
+func (self *Repl) Complete(line string, position int) (string, []string, string) {
+	// The position counts runes, not bytes.
+	runes := []rune(line)
+	start := position
+	for start > 0 && isNameCharacter(runes[start-1]) {
+		start--
+	}
+	head, word, tail := string(runes[:start]), string(runes[start:position]), string(runes[position:])
+	if word == "" {
+		return head, nil, tail
+	}
+
+	candidates := append(interp.KEYWORDS(), interp.CONTEXTUAL_KEYWORDS()...)
+	candidates = append(candidates, self.interpreter.Names()...)
+	if strings.TrimLeft(head, " \t") == ":" {
+		candidates = commandNames()
+		head, word = head[:len(head)-1], ":"+word
+	}
+
+	seen := make(map[string]bool)
+	var completions []string
+	for _, name := range candidates {
+		if strings.HasPrefix(name, word) && !seen[name] {
+			seen[name] = true
+			completions = append(completions, name)
+		}
+	}
+	sort.Strings(completions)
+	return head, completions, tail
+}
+
+func isNameCharacter(character rune) bool {
+	return character == '_' || strings.ContainsRune(interp.LETTER_DIGITS, character)
+}
+
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, HISTORY_FILE)
+}
+
+func (self *Repl) LoadHistory() {
+	file, err := os.Open(historyPath())
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	self.editor.ReadHistory(file)
+}
+
+func (self *Repl) SaveHistory() {
+	path := historyPath()
+	if path == "" {
+		return
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	self.editor.WriteHistory(file)
+}
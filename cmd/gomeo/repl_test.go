@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"gomeo/interp"
+)
+
+func TestComplete(t *testing.T) {
+	repl := &Repl{interpreter: interp.NewInterpreter(interp.Options{})}
+	if _, err := repl.interpreter.Run("<test>", "var counter = 0\nvar count = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line        string
+		position    int
+		head        string
+		completions []string
+		tail        string
+	}{
+		{"", 0, "", nil, ""},
+		{"cou", 3, "", []string{"count", "counter"}, ""},
+		{"1 + coun", 8, "1 + ", []string{"count", "counter"}, ""},
+		{"fu", 2, "", []string{"function"}, ""},
+		{"cou + 1", 3, "", []string{"count", "counter"}, " + 1"},
+		{"1 + ", 4, "1 + ", nil, ""},
+		{":ty", 3, "", []string{":type"}, ""},
+		{":t", 2, "", []string{":time", ":tokens", ":type"}, ""},
+		{"é + cou", 7, "é + ", []string{"count", "counter"}, ""},
+	}
+	for _, test := range tests {
+		head, completions, tail := repl.Complete(test.line, test.position)
+		if head != test.head || !reflect.DeepEqual(completions, test.completions) || tail != test.tail {
+			t.Errorf("Complete(%q, %d) = %q, %q, %q, want %q, %q, %q",
+				test.line, test.position, head, completions, tail, test.head, test.completions, test.tail)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gomeo/interp"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: gomeo [-e program | file | -] [arguments...]")
+		flag.PrintDefaults()
+	}
+	program := flag.String("e", "", "evaluate `program` and exit")
+	flag.Parse()
+
+	evaluate := false
+	flag.Visit(func(f *flag.Flag) {
+		evaluate = evaluate || f.Name == "e"
+	})
+
+	args := flag.Args()
+	switch {
+	case evaluate:
+		os.Exit(runSource("<-e>", *program, args))
+	case len(args) > 0 && args[0] == "-":
+		os.Exit(runStdin(args[1:]))
+	case len(args) > 0:
+		os.Exit(runFile(args[0], args[1:]))
+	case !isTerminal(os.Stdin):
+		os.Exit(runStdin(args))
+	default:
+		shell()
+	}
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func runFile(path string, args []string) int {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return runSource(path, string(content), args)
+}
+
+func runStdin(args []string) int {
+	content, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return runSource("<stdin>", string(content), args)
+}
+
+func runSource(name, text string, args []string) int {
+	interpreter := interp.NewInterpreter(interp.Options{
+		Args:         args,
+		Capabilities: interp.CAPABILITY_ALL,
+	})
+	_, err := interpreter.Run(name, text)
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	if exit, ok := err.(*interp.ExitError); ok {
+		return exit.Code
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gomeo/interp"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, 0},
+		{"exit", &interp.ExitError{Code: 3}, 3},
+		{"exit zero", &interp.ExitError{Code: 0}, 0},
+		{"error", errors.New("failed"), 1},
+	}
+	for _, test := range tests {
+		if got := exitCode(test.err); got != test.want {
+			t.Errorf("%s: got %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	directory, err := ioutil.TempDir("", "gomeo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	path := filepath.Join(directory, "exit.gm")
+	if err := ioutil.WriteFile(path, []byte("exit(4)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path, 4},
+		{filepath.Join(directory, "missing.gm"), 1},
+		{directory, 1},
+	}
+	for _, test := range tests {
+		if got := runFile(test.path, nil); got != test.want {
+			t.Errorf("%s: got %d, want %d", test.path, got, test.want)
+		}
+	}
+}
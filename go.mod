@@ -1,3 +1,5 @@
 module gomeo
 
 go 1.16
+
+require github.com/peterh/liner v1.2.2
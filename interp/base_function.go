@@ -0,0 +1,105 @@
+package interp
+
+import (
+	"fmt"
+	"sort"
+)
+
+type BaseFunction interface {
+	Value
+	Execute(arguments []Value, keywords map[string]Value) *RuntimeResult
+}
+
+func bindArguments(context *Context, names []string, defaults []Value, rest string,
+	arguments []Value, keywords map[string]Value, start, end *Position, caller *Context) *Error {
+
+	if len(arguments) > len(names) && rest == "" {
+		return NewRuntimeError(
+			fmt.Sprintf("%d too many arguments passed into function", len(arguments)-len(names)),
+			start, end, caller,
+		)
+	}
+
+	values := make([]Value, len(names))
+	bound := make([]bool, len(names))
+	for i := range names {
+		if i < len(arguments) {
+			values[i] = arguments[i]
+			bound[i] = true
+		}
+	}
+
+	keywordNames := make([]string, 0, len(keywords))
+	for name := range keywords {
+		keywordNames = append(keywordNames, name)
+	}
+	sort.Strings(keywordNames)
+
+	for _, keyword := range keywordNames {
+		index := -1
+		for i, name := range names {
+			if name == keyword {
+				index = i
+			}
+		}
+		if index < 0 {
+			return NewRuntimeError(
+				fmt.Sprintf("Unknown argument '%s' passed into function", keyword),
+				start, end, caller,
+			)
+		}
+		if bound[index] {
+			return NewRuntimeError(
+				fmt.Sprintf("Argument '%s' passed into function more than once", keyword),
+				start, end, caller,
+			)
+		}
+		values[index] = keywords[keyword]
+		bound[index] = true
+	}
+
+	required := len(names) - len(defaults)
+	missing := 0
+	for i := range names {
+		if bound[i] {
+			continue
+		}
+		if i >= required {
+			if defaults[i-required] == nil {
+				return NewRuntimeError(
+					fmt.Sprintf("Default value of '%s' is not defined yet", names[i]),
+					start, end, caller,
+				)
+			}
+			value, err := context.keep(defaults[i-required], start, end)
+			if err != nil {
+				return err
+			}
+			values[i] = value
+		} else {
+			missing++
+		}
+	}
+	if missing > 0 {
+		return NewRuntimeError(
+			fmt.Sprintf("%d too few arguments passed into function", missing),
+			start, end, caller,
+		)
+	}
+
+	for i, name := range names {
+		if values[i] != nil {
+			values[i].SetContext(context)
+		}
+		context.table.Set(name, values[i])
+	}
+
+	if rest != "" {
+		var extra []Value
+		if len(arguments) > len(names) {
+			extra = arguments[len(names):]
+		}
+		context.table.Set(rest, NewList(extra).SetContext(context))
+	}
+	return nil
+}
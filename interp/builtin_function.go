@@ -1,25 +1,42 @@
-package main
+package interp
 
 import (
-	"bufio"
 	"fmt"
 	"io/ioutil"
 	"math"
-	"os"
 	"os/exec"
 	"strconv"
+	"unicode/utf8"
 )
 
 type BuiltinFunction struct {
 	arguments  []string
+	defaults   []Value
+	rest       string
 	body       func(context *Context) *RuntimeResult
+	capability Capability
 	context    *Context
 	start, end *Position
 }
 
 func NewBuiltinFunction(arguments []string,
 	body func(context *Context) *RuntimeResult) *BuiltinFunction {
-	return &BuiltinFunction{arguments, body, nil, nil, nil}
+	return &BuiltinFunction{arguments, nil, "", body, CAPABILITY_NONE, nil, nil, nil}
+}
+
+func (self *BuiltinFunction) Defaults(values ...Value) *BuiltinFunction {
+	self.defaults = values
+	return self
+}
+
+func (self *BuiltinFunction) Rest(name string) *BuiltinFunction {
+	self.rest = name
+	return self
+}
+
+func (self *BuiltinFunction) Requires(capability Capability) *BuiltinFunction {
+	self.capability = capability
+	return self
 }
 
 func (self *BuiltinFunction) String() string {
@@ -27,7 +44,10 @@ func (self *BuiltinFunction) String() string {
 }
 
 func (self *BuiltinFunction) Copy() Value {
-	res := NewBuiltinFunction(self.arguments, self.body)
+	res := NewBuiltinFunction(self.arguments, self.body).
+		Defaults(self.defaults...).
+		Rest(self.rest).
+		Requires(self.capability)
 	res.SetPosition(self.start, self.end)
 	res.SetContext(self.context)
 	return res
@@ -178,39 +198,44 @@ func (self *BuiltinFunction) Not() (*Number, *Error) {
 	return NewNumber(res), nil
 }
 
-func (self *BuiltinFunction) Execute(arguments []Value) *RuntimeResult {
+func (self *BuiltinFunction) Execute(arguments []Value, keywords map[string]Value) *RuntimeResult {
 	res := NewRuntimeResult()
 
-	context := NewContext("function", self.context, self.start)
-	context.table = NewSymbolTable(context.parent.table)
-
-	if len(arguments) > len(self.arguments) {
-		return res.Failure(NewRuntimeError(
-			fmt.Sprintf(
-				"%d too many arguments passed into function",
-				len(arguments)-len(self.arguments),
-			),
-			self.start, self.end, self.context,
-		))
+	if self.capability != CAPABILITY_NONE {
+		// Without an interpreter nothing is granted, so builtins that touch the host stay closed.
+		granted := CAPABILITY_NONE
+		if self.context != nil && self.context.interpreter != nil {
+			granted = self.context.interpreter.options.Capabilities
+		}
+		if !granted.Has(self.capability) {
+			return res.Failure(NewPermissionError(
+				fmt.Sprintf(
+					"Function needs the '%s' capability, which is not granted",
+					self.capability&^granted,
+				),
+				self.start, self.end, self.context,
+			))
+		}
 	}
-	if len(arguments) < len(self.arguments) {
-		return res.Failure(NewRuntimeError(
-			fmt.Sprintf(
-				"%d too few arguments passed into",
-				len(self.arguments)-len(arguments),
-			),
-			self.start, self.end, self.context,
-		))
+
+	if err := self.context.enter(self.start, self.end); err != nil {
+		return res.Failure(err)
 	}
+	defer self.context.leave()
 
-	for i, argname := range self.arguments {
-		argvalue := arguments[i]
-		argvalue.SetContext(context)
-		context.table.Set(argname, argvalue)
+	context := NewContext("<built-in function>", self.context, self.start)
+	context.table = NewSymbolTable(context.parent.table)
+
+	err := bindArguments(
+		context, self.arguments, self.defaults, self.rest, arguments, keywords,
+		self.start, self.end, self.context,
+	)
+	if err != nil {
+		return res.Failure(err)
 	}
 
 	value := res.Register(self.body(context))
-	if res.error != nil {
+	if res.error != nil || res.shouldExit {
 		return res
 	}
 	return res.Success(value)
@@ -219,40 +244,36 @@ func (self *BuiltinFunction) Execute(arguments []Value) *RuntimeResult {
 //--------------------------------------------------------------------------------------------------
 
 var builtinFunctions map[string]*BuiltinFunction = map[string]*BuiltinFunction{
-	"print": NewBuiltinFunction([]string{"value"}, func(context *Context) *RuntimeResult {
-		fmt.Print(context.table.Get("value"))
+	"print": NewBuiltinFunction([]string{}, func(context *Context) *RuntimeResult {
+		fmt.Fprint(context.interpreter.stdout, joinValues(context.table.Get("values").(*List)))
 		return NewRuntimeResult().Success(nil)
-	}),
+	}).Rest("values"),
 
-	"println": NewBuiltinFunction([]string{"value"}, func(context *Context) *RuntimeResult {
-		fmt.Println(context.table.Get("value"))
+	"println": NewBuiltinFunction([]string{}, func(context *Context) *RuntimeResult {
+		fmt.Fprintln(context.interpreter.stdout, joinValues(context.table.Get("values").(*List)))
 		return NewRuntimeResult().Success(nil)
-	}),
+	}).Rest("values"),
 
 	"printReturn": NewBuiltinFunction([]string{"value"}, func(context *Context) *RuntimeResult {
 		value := context.table.Get("value")
-		fmt.Println(value)
+		fmt.Fprintln(context.interpreter.stdout, value)
 		return NewRuntimeResult().Success(value)
 	}),
 
 	"input": NewBuiltinFunction([]string{"prompt"}, func(context *Context) *RuntimeResult {
 		prompt := context.table.Get("prompt")
-		fmt.Print(prompt)
+		fmt.Fprint(context.interpreter.stdout, prompt)
 
-		scanner := bufio.NewScanner(os.Stdin)
-		scanner.Scan()
-		text := scanner.Text()
+		text := context.interpreter.readLine()
 		res := NewRuntimeResult().Success(NewString(text))
 		return res
-	}),
+	}).Requires(CAPABILITY_STDIN),
 
 	"inputNumber": NewBuiltinFunction([]string{"prompt"}, func(context *Context) *RuntimeResult {
 		prompt := context.table.Get("prompt")
-		fmt.Print(prompt)
+		fmt.Fprint(context.interpreter.stdout, prompt)
 
-		scanner := bufio.NewScanner(os.Stdin)
-		scanner.Scan()
-		text := scanner.Text()
+		text := context.interpreter.readLine()
 		res, err := strconv.ParseFloat(text, 64)
 		if err != nil {
 			return NewRuntimeResult().Failure(NewRuntimeError(
@@ -260,7 +281,7 @@ var builtinFunctions map[string]*BuiltinFunction = map[string]*BuiltinFunction{
 			))
 		}
 		return NewRuntimeResult().Success(NewNumber(res))
-	}),
+	}).Requires(CAPABILITY_STDIN),
 
 	"isNumber": NewBuiltinFunction([]string{"number"}, func(context *Context) *RuntimeResult {
 		number := context.table.Get("number")
@@ -292,6 +313,16 @@ var builtinFunctions map[string]*BuiltinFunction = map[string]*BuiltinFunction{
 		}
 	}),
 
+	"isMap": NewBuiltinFunction([]string{"map"}, func(context *Context) *RuntimeResult {
+		number := context.table.Get("map")
+		switch number.(type) {
+		case *Map:
+			return NewRuntimeResult().Success(NewNumber(1))
+		default:
+			return NewRuntimeResult().Success(NewNumber(0))
+		}
+	}),
+
 	"isFunction": NewBuiltinFunction([]string{"function"}, func(context *Context) *RuntimeResult {
 		number := context.table.Get("function")
 		switch number.(type) {
@@ -304,15 +335,21 @@ var builtinFunctions map[string]*BuiltinFunction = map[string]*BuiltinFunction{
 
 	"clear": NewBuiltinFunction([]string{}, func(context *Context) *RuntimeResult {
 		command := exec.Command("clear")
-		command.Stdout = os.Stdout
+		command.Stdout = context.interpreter.stdout
+		command.Stderr = context.interpreter.stderr
 		_ = command.Run()
 		return NewRuntimeResult().Success(nil)
-	}),
+	}).Requires(CAPABILITY_PROCESS),
 
-	"exit": NewBuiltinFunction([]string{}, func(context *Context) *RuntimeResult {
-		os.Exit(0)
-		return nil
-	}),
+	"exit": NewBuiltinFunction([]string{"code"}, func(context *Context) *RuntimeResult {
+		code, ok := context.table.Get("code").(*Number)
+		if !ok || math.Floor(code.value) != code.value {
+			return NewRuntimeResult().Failure(NewRuntimeError(
+				"Exit code must be a whole number", context.entry, context.entry, context,
+			))
+		}
+		return NewRuntimeResult().SuccessExit(int(code.value))
+	}).Defaults(NewNumber(0)).Requires(CAPABILITY_EXIT),
 
 	"len": NewBuiltinFunction([]string{"value"}, func(context *Context) *RuntimeResult {
 		value := context.table.Get("value")
@@ -322,9 +359,11 @@ var builtinFunctions map[string]*BuiltinFunction = map[string]*BuiltinFunction{
 		case BaseFunction:
 			return NewRuntimeResult().Success(NewNumber(1))
 		case *String:
-			return NewRuntimeResult().Success(NewNumber(float64(len(v.value))))
+			return NewRuntimeResult().Success(NewNumber(float64(utf8.RuneCountInString(v.value))))
 		case *List:
 			return NewRuntimeResult().Success(NewNumber(float64(len(v.values))))
+		case *Map:
+			return NewRuntimeResult().Success(NewNumber(float64(v.size())))
 		default:
 			return NewRuntimeResult().Failure(NewRuntimeError(
 				"'len' not supported for type", context.entry, context.entry, context,
@@ -332,14 +371,46 @@ var builtinFunctions map[string]*BuiltinFunction = map[string]*BuiltinFunction{
 		}
 	}),
 
+	"keys": NewBuiltinFunction([]string{"map"}, func(context *Context) *RuntimeResult {
+		v, ok := context.table.Get("map").(*Map)
+		if !ok {
+			return NewRuntimeResult().Failure(NewRuntimeError(
+				"'keys' parameter must be a map", context.entry, context.entry, context,
+			))
+		}
+
+		keys := make([]Value, v.size())
+		for i, key := range v.keys {
+			keys[i] = key.Copy()
+		}
+		return NewRuntimeResult().Success(NewList(keys))
+	}),
+
 	"change": NewBuiltinFunction([]string{"list", "index", "value"},
 			func(context *Context) *RuntimeResult {
 		v := context.table.Get("list")
 		switch v.(type) {
 		case *List:
+		case *Map:
+			dict := v.(*Map)
+			key := context.table.Get("index")
+			if _, ok := dict.get(key); !ok {
+				err := context.allocate(1, context.entry, context.entry)
+				if err != nil {
+					return NewRuntimeResult().Failure(err)
+				}
+			}
+			if !dict.set(key, context.table.Get("value")) {
+				return NewRuntimeResult().Failure(NewRuntimeError(
+					"'change!' second parameter must be a number or string for a map",
+					context.entry, context.entry, context,
+				))
+			}
+			return NewRuntimeResult().Success(dict)
 		default:
 			return NewRuntimeResult().Failure(NewRuntimeError(
-				"'change!' first parameter must be a list", context.entry, context.entry, context,
+				"'change!' first parameter must be a list or a map",
+				context.entry, context.entry, context,
 			))
 		}
 		list := v.(*List)
@@ -372,10 +443,8 @@ var builtinFunctions map[string]*BuiltinFunction = map[string]*BuiltinFunction{
 		list.values[index] = value
 		return NewRuntimeResult().Success(list)
 	}),
-}
 
-var RUN *BuiltinFunction = NewBuiltinFunction([]string{"file"},
-	func(context *Context) *RuntimeResult {
+	"run": NewBuiltinFunction([]string{"file"}, func(context *Context) *RuntimeResult {
 		res := NewRuntimeResult()
 		file := context.table.Get("file")
 		switch file.(type) {
@@ -395,10 +464,6 @@ var RUN *BuiltinFunction = NewBuiltinFunction([]string{"file"},
 			))
 		}
 
-		value, err2 := run(fileString.value, string(content))
-		if err2 != nil {
-			return res.Failure(err2)
-		}
-
-		return res.Success(value)
-	})
+		return context.interpreter.run(fileString.value, string(content))
+	}).Requires(CAPABILITY_FILESYSTEM),
+}
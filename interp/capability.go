@@ -0,0 +1,48 @@
+package interp
+
+type Capability int
+
+const (
+	CAPABILITY_FILESYSTEM Capability = 1 << iota
+	CAPABILITY_PROCESS
+	CAPABILITY_STDIN
+	CAPABILITY_EXIT
+
+	CAPABILITY_NONE Capability = 0
+	CAPABILITY_ALL  Capability = CAPABILITY_FILESYSTEM | CAPABILITY_PROCESS | CAPABILITY_STDIN |
+		CAPABILITY_EXIT
+)
+
+func (self Capability) Has(capability Capability) bool {
+	return self&capability == capability
+}
+
+func (self Capability) String() string {
+	switch self {
+	case CAPABILITY_NONE:
+		return "none"
+	case CAPABILITY_FILESYSTEM:
+		return "filesystem"
+	case CAPABILITY_PROCESS:
+		return "process"
+	case CAPABILITY_STDIN:
+		return "stdin"
+	case CAPABILITY_EXIT:
+		return "exit"
+	case CAPABILITY_ALL:
+		return "all"
+	}
+
+	res := ""
+	for _, capability := range []Capability{
+		CAPABILITY_FILESYSTEM, CAPABILITY_PROCESS, CAPABILITY_STDIN, CAPABILITY_EXIT,
+	} {
+		if self.Has(capability) {
+			if res != "" {
+				res += "|"
+			}
+			res += capability.String()
+		}
+	}
+	return res
+}
@@ -0,0 +1,65 @@
+package interp
+
+import (
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		text       string
+		capability Capability
+	}{
+		{`run("missing.gm")`, CAPABILITY_FILESYSTEM},
+		{"clear()", CAPABILITY_PROCESS},
+		{`input("")`, CAPABILITY_STDIN},
+		{`inputNumber("")`, CAPABILITY_STDIN},
+		{"exit(0)", CAPABILITY_EXIT},
+	}
+
+	for _, test := range tests {
+		_, err := NewInterpreter(Options{}).Run("<test>", test.text)
+		if errorName(err) != "PermissionError" {
+			t.Errorf("%s without capabilities: got %v, want PermissionError", test.text, err)
+		}
+
+		granted := CAPABILITY_ALL &^ test.capability
+		_, err = NewInterpreter(Options{Capabilities: granted}).Run("<test>", test.text)
+		if errorName(err) != "PermissionError" {
+			t.Errorf("%s without %s: got %v, want PermissionError", test.text, test.capability, err)
+		}
+	}
+
+	_, err := NewInterpreter(Options{Capabilities: CAPABILITY_EXIT}).Run("<test>", "exit(0)")
+	if errorName(err) == "PermissionError" {
+		t.Errorf("exit was denied although the capability was granted")
+	}
+}
+
+func TestCapabilityString(t *testing.T) {
+	tests := []struct {
+		capability Capability
+		want       string
+	}{
+		{CAPABILITY_NONE, "none"},
+		{CAPABILITY_ALL, "all"},
+		{CAPABILITY_STDIN, "stdin"},
+		{CAPABILITY_FILESYSTEM | CAPABILITY_EXIT, "filesystem|exit"},
+	}
+	for _, test := range tests {
+		if test.capability.String() != test.want {
+			t.Errorf("got %s, want %s", test.capability, test.want)
+		}
+	}
+}
+
+func TestCapabilitiesWithoutInterpreter(t *testing.T) {
+	for _, name := range []string{"run", "clear", "input", "inputNumber", "exit"} {
+		function := builtinFunctions[name].Copy().(*BuiltinFunction)
+		function.SetContext(NewContext("<test>", nil, nil))
+
+		res := function.Execute(nil, nil)
+		if res.error == nil || res.error.Name() != "PermissionError" {
+			t.Errorf("%s without an interpreter: got %v, want PermissionError", name, res.error)
+		}
+	}
+}
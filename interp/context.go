@@ -0,0 +1,104 @@
+package interp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+const MAX_DEPTH = 10000
+
+type Context struct {
+	name        string
+	parent      *Context
+	entry       *Position
+	table       *SymbolTable
+	interpreter *Interpreter
+}
+
+func NewContext(name string, parent *Context, entry *Position) *Context {
+	res := &Context{name, parent, entry, nil, nil}
+	if parent != nil {
+		res.interpreter = parent.interpreter
+	}
+	return res
+}
+
+func (self *Context) checkpoint(start, end *Position) *Error {
+	if self.interpreter == nil {
+		return nil
+	}
+
+	if atomic.CompareAndSwapInt32(&self.interpreter.interrupted, 1, 0) {
+		return NewKeyboardInterruptError(start, end, self)
+	}
+
+	self.interpreter.steps++
+	limit := self.interpreter.options.MaxSteps
+	if limit > 0 && self.interpreter.steps > limit {
+		return NewStepLimitError(limit, start, end, self)
+	}
+
+	ctx := self.interpreter.ctx
+	select {
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return NewDeadlineExceededError(start, end, self)
+		}
+		return NewCancelledError(start, end, self)
+	default:
+		return nil
+	}
+}
+
+func (self *Context) allocate(amount float64, start, end *Position) *Error {
+	if self == nil || self.interpreter == nil {
+		return nil
+	}
+
+	limit := self.interpreter.options.MaxAllocation
+	if limit > 0 && float64(self.interpreter.allocated)+amount > float64(limit) {
+		return NewMemoryLimitError(limit, start, end, self)
+	}
+	self.interpreter.allocated += int(amount)
+	return nil
+}
+
+// keep copies value before it is stored in a variable, an index or an argument, and charges the
+// copied elements to the allocation limit.
+func (self *Context) keep(value Value, start, end *Position) (Value, *Error) {
+	if value == nil {
+		return nil, nil
+	}
+	if err := self.allocate(float64(elements(value)), start, end); err != nil {
+		return nil, err
+	}
+	return clone(value), nil
+}
+
+// enter counts a function call, and fails before the calls nest so deep that the go stack runs
+// out. Every successful enter must be followed by a leave.
+func (self *Context) enter(start, end *Position) *Error {
+	if self == nil || self.interpreter == nil {
+		return nil
+	}
+
+	limit := self.interpreter.options.MaxDepth
+	if limit <= 0 {
+		limit = MAX_DEPTH
+	}
+	if self.interpreter.depth >= limit {
+		return NewRuntimeError(
+			fmt.Sprintf("Exceeded the limit of %d nested function calls", limit), start, end, self,
+		)
+	}
+	self.interpreter.depth++
+	return nil
+}
+
+func (self *Context) leave() {
+	if self == nil || self.interpreter == nil {
+		return
+	}
+	self.interpreter.depth--
+}
@@ -0,0 +1,184 @@
+package interp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func errorName(err error) string {
+	var scriptError *Error
+	if errors.As(err, &scriptError) {
+		return scriptError.Name()
+	}
+	return ""
+}
+
+func TestEvalContext(t *testing.T) {
+	interpreter := NewInterpreter(Options{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := interpreter.EvalContext(ctx, "while TRUE do 0 end")
+	if errorName(err) != "DeadlineExceededError" {
+		t.Errorf("got %v, want DeadlineExceededError", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = interpreter.EvalContext(ctx, "var f = function() do f() end\nf()")
+	if errorName(err) != "CancelledError" {
+		t.Errorf("got %v, want CancelledError", err)
+	}
+
+	value, err := interpreter.EvalContext(context.Background(), "1 + 1")
+	if err != nil || value.String() != "2" {
+		t.Errorf("the interpreter did not recover after a cancelled run: %v, %v", value, err)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	interpreter := NewInterpreter(Options{})
+
+	// Interrupting before Run has started would be cleared by the run itself, so the script
+	// signals once it is running.
+	started := make(chan bool, 1)
+	err := interpreter.Register("started", func() { started <- true })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := interpreter.Run("<test>", "started()\nvar i = 0\nwhile TRUE do i = i + 1 end")
+		done <- err
+	}()
+
+	<-started
+	interpreter.Interrupt()
+	select {
+	case err := <-done:
+		if errorName(err) != "KeyboardInterrupt" {
+			t.Errorf("got %v, want KeyboardInterrupt", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run was not interrupted")
+	}
+}
+
+func TestMaxSteps(t *testing.T) {
+	interpreter := NewInterpreter(Options{MaxSteps: 100})
+
+	if _, err := interpreter.Run("<test>", "for i from 0 to 50 do i end"); err != nil {
+		t.Errorf("a run within the limit failed: %v", err)
+	}
+	if interpreter.Steps() == 0 {
+		t.Error("steps were not counted")
+	}
+
+	_, err := interpreter.Run("<test>", "for i from 0 to 1000 do i end")
+	if errorName(err) != "StepLimitError" {
+		t.Errorf("got %v, want StepLimitError", err)
+	}
+
+	if _, err := interpreter.Run("<test>", "for i from 0 to 50 do i end"); err != nil {
+		t.Errorf("the step count was not reset between runs: %v", err)
+	}
+}
+
+func TestMaxAllocation(t *testing.T) {
+	entries := make([]string, 101)
+	for i := range entries {
+		entries[i] = fmt.Sprintf("%d: 0", i)
+	}
+
+	tests := []string{
+		"[1] ^ 1000000000",
+		`"ab" * 1000000000`,
+		"var l = []\nwhile TRUE do l = l + 1 end",
+		"for i from 0 to 1000 do i end",
+		"var a = [0] ^ 40\nvar b = a\nvar c = a",
+		"var a = [0] ^ 40\nvar f = function(l) do 0 end\nf(a)\nf(a)",
+		"[" + strings.Repeat("0, ", 100) + "0]",
+		"{" + strings.Join(entries, ", ") + "}",
+	}
+	for _, text := range tests {
+		_, err := NewInterpreter(Options{MaxAllocation: 100}).Run("<test>", text)
+		if errorName(err) != "MemoryLimitError" {
+			t.Errorf("%q: got %v, want MemoryLimitError", text, err)
+		}
+	}
+
+	nested := "var a = [0] ^ 50000\n" +
+		"var b = [a, a, a, a, a, a, a, a, a, a]\n" +
+		"var c = [b, b, b, b, b, b, b, b, b, b]\n" +
+		"var d = [c, c, c, c, c, c, c, c, c, c]"
+	_, err := NewInterpreter(Options{MaxAllocation: 100000}).Run("<test>", nested)
+	if errorName(err) != "MemoryLimitError" {
+		t.Errorf("nested literals: got %v, want MemoryLimitError", err)
+	}
+
+	for _, text := range []string{"[1, 2] ^ 10", "var g = [0] ^ 40\nfor i from 0 to 10 do len(g) end"} {
+		if _, err := NewInterpreter(Options{MaxAllocation: 100}).Run("<test>", text); err != nil {
+			t.Errorf("%q: an allocation within the limit failed: %v", text, err)
+		}
+	}
+}
+
+func TestCallContext(t *testing.T) {
+	interpreter := NewInterpreter(Options{})
+	_, err := interpreter.Run("<test>", "var spin = function() do while TRUE do 0 end end")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = interpreter.CallContext(ctx, "spin")
+	if errorName(err) != "DeadlineExceededError" {
+		t.Errorf("got %v, want DeadlineExceededError", err)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	text := "function f(n) do\nif n == 0 do return 0 end\nf(n - 1)\nend\nf(depth)"
+	tests := []struct {
+		options Options
+		depth   float64
+		fails   bool
+	}{
+		{Options{}, MAX_DEPTH - 1, false},
+		{Options{}, MAX_DEPTH, true},
+		{Options{}, 1e9, true},
+		{Options{MaxDepth: 50}, 49, false},
+		{Options{MaxDepth: 50}, 50, true},
+	}
+
+	for _, test := range tests {
+		test.options.Globals = map[string]Value{"depth": NewNumber(test.depth)}
+		interpreter := NewInterpreter(test.options)
+
+		_, err := interpreter.Run("<test>", text)
+		if !test.fails && err != nil {
+			t.Errorf("depth %g with limit %d failed: %v", test.depth, test.options.MaxDepth, err)
+		}
+		if test.fails {
+			if errorName(err) != "RuntimeError" ||
+				!strings.Contains(err.(*Error).Details(), "nested function calls") {
+				t.Errorf("depth %g with limit %d: got %v, want RuntimeError",
+					test.depth, test.options.MaxDepth, err)
+				continue
+			}
+			if lines := strings.Count(err.Error(), "\n"); lines > 20 {
+				t.Errorf("the traceback has %d lines, repeated lines were not collapsed", lines)
+			}
+		}
+
+		if _, err := interpreter.Run("<test>", "f(10)"); err != nil {
+			t.Errorf("the depth was not reset after a run: %v", err)
+		}
+	}
+}
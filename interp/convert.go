@@ -0,0 +1,308 @@
+package interp
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+var valueType reflect.Type = reflect.TypeOf((*Value)(nil)).Elem()
+var errorType reflect.Type = reflect.TypeOf((*error)(nil)).Elem()
+var interfaceType reflect.Type = reflect.TypeOf((*interface{})(nil)).Elem()
+
+func TypeName(value Value) string {
+	switch value.(type) {
+	case nil:
+		return "nothing"
+	case *Number:
+		return "number"
+	case *String:
+		return "string"
+	case *List:
+		return "list"
+	case *Map:
+		return "map"
+	case BaseFunction:
+		return "function"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func ToGo(value Value) (interface{}, error) {
+	res, err := toGo(value, interfaceType)
+	if err != nil {
+		return nil, err
+	}
+	return res.Interface(), nil
+}
+
+func FromGo(value interface{}) (Value, error) {
+	return fromGo(reflect.ValueOf(value))
+}
+
+func toGo(value Value, target reflect.Type) (reflect.Value, error) {
+	generic := target.Kind() == reflect.Interface && target.NumMethod() == 0
+	if value != nil && !generic && reflect.TypeOf(value).AssignableTo(target) {
+		return reflect.ValueOf(value), nil
+	}
+
+	switch target.Kind() {
+	case reflect.Float32, reflect.Float64:
+		number, ok := value.(*Number)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected number, got %s", TypeName(value))
+		}
+		return reflect.ValueOf(number.value).Convert(target), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := value.(*Number)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected number, got %s", TypeName(value))
+		}
+		if math.Floor(number.value) != number.value {
+			return reflect.Value{}, fmt.Errorf("expected whole number, got %s", number)
+		}
+		res := reflect.New(target).Elem()
+		switch target.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if number.value < 0 || res.OverflowUint(uint64(number.value)) {
+				return reflect.Value{}, fmt.Errorf("number %s out of range", number)
+			}
+			res.SetUint(uint64(number.value))
+		default:
+			if res.OverflowInt(int64(number.value)) {
+				return reflect.Value{}, fmt.Errorf("number %s out of range", number)
+			}
+			res.SetInt(int64(number.value))
+		}
+		return res, nil
+
+	case reflect.Bool:
+		if value == nil {
+			return reflect.ValueOf(false), nil
+		}
+		return reflect.ValueOf(value.IsTrue()), nil
+
+	case reflect.String:
+		str, ok := value.(*String)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected string, got %s", TypeName(value))
+		}
+		return reflect.ValueOf(str.value).Convert(target), nil
+
+	case reflect.Slice:
+		list, ok := value.(*List)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected list, got %s", TypeName(value))
+		}
+		res := reflect.MakeSlice(target, len(list.values), len(list.values))
+		for i, item := range list.values {
+			converted, err := toGo(item, target.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %s", i, err)
+			}
+			res.Index(i).Set(converted)
+		}
+		return res, nil
+
+	case reflect.Map:
+		dict, ok := value.(*Map)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected map, got %s", TypeName(value))
+		}
+		res := reflect.MakeMapWithSize(target, dict.size())
+		for _, key := range dict.keys {
+			item, _ := dict.get(key)
+			convertedKey, err := toGo(key, target.Key())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %s: %s", key, err)
+			}
+			convertedItem, err := toGo(item, target.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %s: %s", key, err)
+			}
+			res.SetMapIndex(convertedKey, convertedItem)
+		}
+		return res, nil
+
+	case reflect.Struct:
+		dict, ok := value.(*Map)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected map, got %s", TypeName(value))
+		}
+		res := reflect.New(target).Elem()
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			item, ok := dict.get(NewString(field.Name))
+			if !ok {
+				continue
+			}
+			converted, err := toGo(item, field.Type)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %s", field.Name, err)
+			}
+			res.Field(i).Set(converted)
+		}
+		return res, nil
+
+	case reflect.Interface:
+		if !generic {
+			break
+		}
+		var res interface{}
+		switch v := value.(type) {
+		case nil:
+			return reflect.Zero(target), nil
+		case *Number:
+			res = v.value
+		case *String:
+			res = v.value
+		case *List:
+			items, err := toGo(v, reflect.SliceOf(interfaceType))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			res = items.Interface()
+		case *Map:
+			items, err := toGo(v, reflect.MapOf(interfaceType, interfaceType))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			res = items.Interface()
+		case BaseFunction:
+			return reflect.Value{}, fmt.Errorf(
+				"cannot convert function to a go value, use Interpreter.Call instead",
+			)
+		default:
+			return reflect.Value{}, fmt.Errorf("cannot convert %s to a go value", TypeName(value))
+		}
+		return reflect.ValueOf(&res).Elem(), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", TypeName(value), target)
+}
+
+func fromGo(value reflect.Value) (Value, error) {
+	if !value.IsValid() {
+		return nil, nil
+	}
+
+	if value.Type().Implements(valueType) {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil, nil
+		}
+		return value.Interface().(Value), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return NewNumber(value.Float()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewNumber(float64(value.Int())), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return NewNumber(float64(value.Uint())), nil
+
+	case reflect.Bool:
+		if value.Bool() {
+			return NewNumber(1), nil
+		}
+		return NewNumber(0), nil
+
+	case reflect.String:
+		return NewString(value.String()), nil
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return NewList(nil), nil
+		}
+		values := make([]Value, value.Len())
+		for i := range values {
+			item, err := fromGo(value.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %s", i, err)
+			}
+			values[i] = item
+		}
+		return NewList(values), nil
+
+	case reflect.Map:
+		if value.IsNil() {
+			return NewMap(), nil
+		}
+		keys := make([]Value, 0, value.Len())
+		items := make(map[Value]reflect.Value, value.Len())
+		for _, k := range value.MapKeys() {
+			key, err := fromGo(k)
+			if err != nil {
+				return nil, fmt.Errorf("key %v: %s", k, err)
+			}
+			if _, ok := mapKey(key); !ok {
+				return nil, fmt.Errorf("key %v: map keys must be numbers or strings", k)
+			}
+			keys = append(keys, key)
+			items[key] = value.MapIndex(k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessKey(keys[i], keys[j])
+		})
+
+		res := NewMap()
+		for _, key := range keys {
+			item, err := fromGo(items[key])
+			if err != nil {
+				return nil, fmt.Errorf("item %s: %s", key, err)
+			}
+			res.set(key, item)
+		}
+		return res, nil
+
+	case reflect.Struct:
+		res := NewMap()
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			item, err := fromGo(value.Field(i))
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %s", field.Name, err)
+			}
+			res.set(NewString(field.Name), item)
+		}
+		return res, nil
+
+	case reflect.Interface, reflect.Ptr:
+		if value.IsNil() {
+			return nil, nil
+		}
+		return fromGo(value.Elem())
+
+	case reflect.Func:
+		return nil, fmt.Errorf(
+			"cannot convert function %s to a gomeo value, use Interpreter.Register instead",
+			value.Type(),
+		)
+	}
+
+	return nil, fmt.Errorf("cannot convert %s to a gomeo value", value.Type())
+}
+
+func lessKey(a, b Value) bool {
+	switch x := a.(type) {
+	case *Number:
+		y, ok := b.(*Number)
+		return !ok || x.value < y.value
+	case *String:
+		y, ok := b.(*String)
+		return ok && x.value < y.value
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package interp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToGo(t *testing.T) {
+	list := NewList([]Value{NewNumber(1), NewString("a")})
+	dict := NewMap()
+	dict.set(NewString("a"), NewNumber(1))
+	dict.set(NewNumber(2), list)
+
+	tests := []struct {
+		value Value
+		want  interface{}
+	}{
+		{nil, nil},
+		{NewNumber(1.5), 1.5},
+		{NewString("a"), "a"},
+		{list, []interface{}{1.0, "a"}},
+		{dict, map[interface{}]interface{}{"a": 1.0, 2.0: []interface{}{1.0, "a"}}},
+	}
+	for _, test := range tests {
+		got, err := ToGo(test.value)
+		if err != nil {
+			t.Errorf("ToGo(%v): %s", test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ToGo(%v) = %#v, want %#v", test.value, got, test.want)
+		}
+	}
+
+	function := NewFunction("f", nil, nil, nil)
+	for _, value := range []Value{
+		function,
+		builtinFunctions["print"],
+		NewList([]Value{NewNumber(1), function}),
+	} {
+		if _, err := ToGo(value); err == nil {
+			t.Errorf("ToGo(%v) should fail", value)
+		}
+	}
+}
+
+func TestFromGo(t *testing.T) {
+	type point struct {
+		X, Y   int
+		hidden int
+	}
+
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{1, "1"},
+		{uint8(2), "2"},
+		{2.5, "2.5"},
+		{true, "1"},
+		{"a", "a"},
+		{[]int{1, 2}, "[1, 2]"},
+		{[2]string{"a", "b"}, "[a, b]"},
+		{map[string]int{"b": 2, "a": 1}, "{a: 1, b: 2}"},
+		{point{1, 2, 3}, "{X: 1, Y: 2}"},
+		{&point{1, 2, 3}, "{X: 1, Y: 2}"},
+	}
+	for _, test := range tests {
+		got, err := FromGo(test.value)
+		if err != nil {
+			t.Errorf("FromGo(%#v): %s", test.value, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("FromGo(%#v) = %s, want %s", test.value, got, test.want)
+		}
+	}
+
+	if got, err := FromGo(nil); got != nil || err != nil {
+		t.Errorf("FromGo(nil) = %v, %v", got, err)
+	}
+	for _, value := range []interface{}{func() {}, make(chan int), map[[2]int]int{{1, 2}: 3}} {
+		if _, err := FromGo(value); err == nil {
+			t.Errorf("FromGo(%T) should fail", value)
+		}
+	}
+}
+
+func TestToGoTyped(t *testing.T) {
+	type config struct {
+		Name  string
+		Ports []int
+	}
+
+	value, err := FromGo(map[string]interface{}{"Name": "web", "Ports": []int{80, 443}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted, err := toGo(value, reflect.TypeOf(config{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := config{"web", []int{80, 443}}
+	if !reflect.DeepEqual(converted.Interface(), want) {
+		t.Errorf("got %#v, want %#v", converted.Interface(), want)
+	}
+}
@@ -0,0 +1,151 @@
+package interp
+
+import (
+	"fmt"
+)
+
+type Error struct {
+	name    string
+	details string
+	start   *Position
+	end     *Position
+	context *Context
+}
+
+func NewError(name, details string, start, end *Position, context *Context) *Error {
+	return &Error{name, details, start, end, context}
+}
+
+func (self *Error) AsString() string {
+	if self.context == nil {
+		result := fmt.Sprintf("%s: %s\n", self.name, self.details)
+		result += fmt.Sprintf("File %s, line %d", self.start.name, self.start.line+1)
+		result += fmt.Sprintf("\n\n%s", stringWithArrows(self.start.text, self.start, self.end))
+		return result
+	} else {
+		result := self.GenerateTraceback()
+		result += fmt.Sprintf("%s: %s\n", self.name, self.details)
+		result += fmt.Sprintf("\n\n%s", stringWithArrows(self.start.text, self.start, self.end))
+		return result
+	}
+}
+
+func (self *Error) Name() string {
+	return self.name
+}
+
+func (self *Error) Details() string {
+	return self.details
+}
+
+func (self *Error) Start() *Position {
+	return self.start
+}
+
+func (self *Error) End() *Position {
+	return self.end
+}
+
+func (self *Error) Incomplete() bool {
+	if self.context != nil || self.start == nil {
+		return false
+	}
+	return self.start.index >= len(self.start.text)
+}
+
+func (self *Error) Error() string {
+	return self.AsString()
+}
+
+type ExitError struct {
+	Code int
+}
+
+func (self *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", self.Code)
+}
+
+func NewIllegalCharacterError(details string, start, end *Position) *Error {
+	return NewError("IllegalCharacterError", details, start, end, nil)
+}
+
+func NewExpectedCharacterError(details string, start, end *Position) *Error {
+	return NewError("ExpectedCharacterError", details, start, end, nil)
+}
+
+func NewInvalidSyntaxError(details string, start, end *Position) *Error {
+	return NewError("InvalidSyntaxError", details, start, end, nil)
+}
+
+func NewRuntimeError(details string, start, end *Position, context *Context) *Error {
+	return NewError("RuntimeError", details, start, end, context)
+}
+
+func NewPermissionError(details string, start, end *Position, context *Context) *Error {
+	return NewError("PermissionError", details, start, end, context)
+}
+
+func NewKeyboardInterruptError(start, end *Position, context *Context) *Error {
+	return NewError("KeyboardInterrupt", "Execution was interrupted", start, end, context)
+}
+
+func NewCancelledError(start, end *Position, context *Context) *Error {
+	return NewError("CancelledError", "Execution was cancelled", start, end, context)
+}
+
+func NewDeadlineExceededError(start, end *Position, context *Context) *Error {
+	return NewError("DeadlineExceededError", "Execution deadline exceeded", start, end, context)
+}
+
+func NewStepLimitError(limit int, start, end *Position, context *Context) *Error {
+	return NewError(
+		"StepLimitError", fmt.Sprintf("Exceeded the limit of %d steps", limit), start, end, context,
+	)
+}
+
+func NewMemoryLimitError(limit int, start, end *Position, context *Context) *Error {
+	return NewError(
+		"MemoryLimitError", fmt.Sprintf("Exceeded the limit of %d allocated elements", limit),
+		start, end, context,
+	)
+}
+
+func (self *Error) GenerateTraceback() string {
+	var lines []string
+	position := self.start
+	context := self.context
+
+	for context != nil {
+		lines = append(lines, fmt.Sprintf(
+			"  File %s, line %d, in %s\n", position.name, position.line+1, context.name,
+		))
+		position = context.entry
+		context = context.parent
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	// Deep recursion repeats the same line thousands of times, so only the first few are shown.
+	res := ""
+	repeated := 0
+	for i, line := range lines {
+		if i > 0 && line == lines[i-1] {
+			repeated++
+		} else {
+			repeated = 0
+		}
+		if repeated < 3 {
+			res += line
+		}
+		if repeated >= 3 && (i == len(lines)-1 || lines[i+1] != line) {
+			times := "times"
+			if repeated == 3 {
+				times = "time"
+			}
+			res += fmt.Sprintf("  [Previous line repeated %d more %s]\n", repeated-2, times)
+		}
+	}
+
+	return "Traceback (most recent call last):\n" + res
+}
@@ -1,26 +1,45 @@
-package main
+package interp
 
 import (
 	"fmt"
 )
 
 type Function struct {
+	name       string
 	arguments  []string
+	defaults   []Value
+	rest       string
 	body       Node
+	scope      *SymbolTable
 	start, end *Position
 	context    *Context
 }
 
-func NewFunction(arguments []string, body Node) *Function {
-	return &Function{arguments, body, nil, nil, nil}
+func NewFunction(name string, arguments []string, body Node, scope *SymbolTable) *Function {
+	return &Function{name, arguments, nil, "", body, scope, nil, nil, nil}
+}
+
+func (self *Function) Defaults(values ...Value) *Function {
+	self.defaults = values
+	return self
+}
+
+func (self *Function) Rest(name string) *Function {
+	self.rest = name
+	return self
 }
 
 func (self *Function) String() string {
-	return "<function>"
+	if self.name == "" {
+		return "<function>"
+	}
+	return fmt.Sprintf("<function %s>", self.name)
 }
 
 func (self *Function) Copy() Value {
-	res := NewFunction(self.arguments, self.body)
+	res := NewFunction(self.name, self.arguments, self.body, self.scope).
+		Defaults(self.defaults...).
+		Rest(self.rest)
 	res.SetPosition(self.start, self.end)
 	res.SetContext(self.context)
 	return res
@@ -141,35 +160,47 @@ func (self *Function) IsTrue() bool {
 	return false
 }
 
-func (self *Function) Execute(arguments []Value) *RuntimeResult {
+func (self *Function) Execute(arguments []Value, keywords map[string]Value) *RuntimeResult {
 	res := NewRuntimeResult()
 
-	if len(arguments) > len(self.arguments) {
-		return res.Failure(NewRuntimeError(
-			fmt.Sprintf(
-				"%d too many arguments passed into function",
-				len(arguments)-len(self.arguments),
-			),
-			self.start, self.end, self.context,
-		))
-	}
-	if len(arguments) < len(self.arguments) {
-		return res.Failure(NewRuntimeError(
-			fmt.Sprintf(
-				"%d too few arguments passed into",
-				len(self.arguments)-len(arguments),
-			),
-			self.start, self.end, self.context,
-		))
+	if err := self.context.enter(self.start, self.end); err != nil {
+		return res.Failure(err)
 	}
+	defer self.context.leave()
 
-	context := NewContext("function", self.context, self.start)
-	context.table = NewSymbolTable(context.parent.table)
+	// The context only records where the function was called from for tracebacks, names are
+	// looked up in the scope the function was defined in.
+	name := self.name
+	if name == "" {
+		name = "<function>"
+	}
+	context := NewContext(name, self.context, self.start)
+	context.table = NewSymbolTable(self.scope)
+
+	// Arguments are copied like assignments, so the function can not change the caller's lists.
+	copies := make([]Value, len(arguments))
+	for i, argument := range arguments {
+		kept, err := self.context.keep(argument, self.start, self.end)
+		if err != nil {
+			return res.Failure(err)
+		}
+		copies[i] = kept
+	}
+	keywordCopies := make(map[string]Value)
+	for name, argument := range keywords {
+		kept, err := self.context.keep(argument, self.start, self.end)
+		if err != nil {
+			return res.Failure(err)
+		}
+		keywordCopies[name] = kept
+	}
 
-	for i, argname := range self.arguments {
-		argvalue := arguments[i]
-		argvalue.SetContext(context)
-		context.table.Set(argname, argvalue)
+	err := bindArguments(
+		context, self.arguments, self.defaults, self.rest, copies, keywordCopies,
+		self.start, self.end, self.context,
+	)
+	if err != nil {
+		return res.Failure(err)
 	}
 
 	value := res.Register(self.body.Interpret(context))
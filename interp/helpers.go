@@ -0,0 +1,272 @@
+package interp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+func repeat(values []Value, number int) []Value {
+	if number < 0 {
+		return nil
+	}
+
+	res := make([]Value, len(values)*number)
+	for i, value := range values {
+		for j := 0; j < number; j++ {
+			res[i+j*len(values)] = value
+		}
+	}
+	return res
+}
+
+func remove(values []Value, index int) []Value {
+	if index < 0 || index > len(values)-1 {
+		return nil
+	}
+
+	res := make([]Value, len(values)-1)
+	for i := 0; i < index; i++ {
+		res[i] = values[i]
+	}
+	for i := index + 1; i < len(values); i++ {
+		res[i-1] = values[i]
+	}
+	return res
+}
+
+func toIndex(index Value, length int, context *Context) (int, *Error) {
+	number, ok := index.(*Number)
+	if !ok {
+		return 0, NewRuntimeError(
+			fmt.Sprintf("Index must be a number, not %s", TypeName(index)),
+			index.Start(), index.End(), context,
+		)
+	}
+	if math.Floor(number.value) != number.value {
+		return 0, NewRuntimeError(
+			"Index must not be fractional", index.Start(), index.End(), context,
+		)
+	}
+
+	res := int(number.value)
+	if res < 0 {
+		res += length
+	}
+	if res < 0 || res >= length {
+		return 0, NewRuntimeError(
+			fmt.Sprintf("Index out of range (length %d, index %d)", length, int(number.value)),
+			index.Start(), index.End(), context,
+		)
+	}
+	return res, nil
+}
+
+func toBound(bound Value, context *Context) (int, *Error) {
+	number, ok := bound.(*Number)
+	if !ok {
+		return 0, NewRuntimeError(
+			fmt.Sprintf("Slice bounds must be numbers, not %s", TypeName(bound)),
+			bound.Start(), bound.End(), context,
+		)
+	}
+	if math.Floor(number.value) != number.value {
+		return 0, NewRuntimeError(
+			"Slice bounds must not be fractional", bound.Start(), bound.End(), context,
+		)
+	}
+	return int(number.value), nil
+}
+
+func sliceIndices(length int, start, end, step Value, context *Context) ([]int, *Error) {
+	stepInt := 1
+	if step != nil {
+		var err *Error
+		stepInt, err = toBound(step, context)
+		if err != nil {
+			return nil, err
+		}
+		if stepInt == 0 {
+			return nil, NewRuntimeError(
+				"Slice step must not be zero", step.Start(), step.End(), context,
+			)
+		}
+	}
+
+	lower, upper := 0, length
+	if stepInt < 0 {
+		lower, upper = -1, length-1
+	}
+
+	clamp := func(bound Value, missing int) (int, *Error) {
+		if bound == nil {
+			return missing, nil
+		}
+		res, err := toBound(bound, context)
+		if err != nil {
+			return 0, err
+		}
+		if res < 0 {
+			return max(res+length, lower), nil
+		}
+		return min(res, upper), nil
+	}
+
+	var startInt, endInt int
+	var err *Error
+	if stepInt > 0 {
+		startInt, err = clamp(start, lower)
+		if err == nil {
+			endInt, err = clamp(end, upper)
+		}
+	} else {
+		startInt, err = clamp(start, upper)
+		if err == nil {
+			endInt, err = clamp(end, lower)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res []int
+	for i := startInt; (stepInt > 0 && i < endInt) || (stepInt < 0 && i > endInt); i += stepInt {
+		res = append(res, i)
+	}
+	return res, nil
+}
+
+// view gives value its own position and context. Lists and maps share their elements with value,
+// reading a variable makes a view and storing it makes a copy with clone.
+func view(value Value) Value {
+	switch v := value.(type) {
+	case *List:
+		return &List{v.values, v.start, v.end, v.context}
+	case *Map:
+		return &Map{v.keys, v.values, v.start, v.end, v.context}
+	default:
+		return value.Copy()
+	}
+}
+
+// clone copies value including the lists and maps inside it.
+func clone(value Value) Value {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case *List:
+		values := make([]Value, len(v.values))
+		for i, item := range v.values {
+			values[i] = clone(item)
+		}
+		return NewList(values).SetPosition(v.start, v.end).SetContext(v.context)
+	case *Map:
+		res := NewMap()
+		for _, key := range v.keys {
+			item, _ := v.get(key)
+			res.set(key, clone(item))
+		}
+		return res.SetPosition(v.start, v.end).SetContext(v.context)
+	default:
+		return value.Copy()
+	}
+}
+
+// elements counts the items that clone copies.
+func elements(value Value) int {
+	res := 0
+	switch v := value.(type) {
+	case *List:
+		for _, item := range v.values {
+			res += 1 + elements(item)
+		}
+	case *Map:
+		for _, key := range v.keys {
+			item, _ := v.get(key)
+			res += 1 + elements(item)
+		}
+	}
+	return res
+}
+
+func joinValues(list *List) string {
+	res := make([]string, len(list.values))
+	for i, value := range list.values {
+		res[i] = value.String()
+	}
+	return strings.Join(res, " ")
+}
+
+func max(a, b int) int {
+	if a < b {
+		return b
+	}
+	return a
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func indentation(line string, column int) string {
+	res := ""
+	for i, character := range line {
+		if i >= column {
+			break
+		}
+		if character == '\t' {
+			res += "\t"
+		} else {
+			res += " "
+		}
+	}
+	return res + strings.Repeat(" ", max(column-len(line), 0))
+}
+
+func stringWithArrows(text string, start, end *Position) string {
+	if len(text) == 0 {
+		return ""
+	}
+
+	res := ""
+
+	index_start := strings.LastIndex(text[0:min(start.index, len(text))], "\n") + 1
+
+	line_count := end.line - start.line + 1
+	for i := 0; i < line_count; i++ {
+		index_end := strings.IndexRune(text[index_start:], '\n')
+		if index_end < 0 {
+			index_end = len(text)
+		} else {
+			index_end += index_start
+		}
+		line := text[index_start:index_end]
+
+		var column_start int
+		if i == 0 {
+			column_start = start.column
+		} else {
+			column_start = 0
+		}
+
+		var column_end int
+		if i == line_count-1 {
+			column_end = end.column
+		} else {
+			column_end = len(line)
+		}
+
+		if i > 0 {
+			res += "\n"
+		}
+		res += line + "\n"
+		res += indentation(line, column_start) + strings.Repeat("^", max(column_end-column_start, 1))
+
+		index_start = min(index_end+1, len(text))
+	}
+
+	return res
+}
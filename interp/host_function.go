@@ -0,0 +1,88 @@
+package interp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func NewHostFunction(function interface{}) (*BuiltinFunction, error) {
+	callable := reflect.ValueOf(function)
+	if callable.Kind() != reflect.Func {
+		return nil, fmt.Errorf("expected a function, got %T", function)
+	}
+
+	functionType := callable.Type()
+	if functionType.IsVariadic() {
+		return nil, fmt.Errorf("variadic functions are not supported")
+	}
+
+	returnsError := false
+	switch functionType.NumOut() {
+	case 0:
+	case 1:
+		returnsError = functionType.Out(0) == errorType
+	case 2:
+		if functionType.Out(1) != errorType {
+			return nil, fmt.Errorf("second return value must be an error")
+		}
+		returnsError = true
+	default:
+		return nil, fmt.Errorf("functions may return at most a value and an error")
+	}
+
+	arguments := make([]string, functionType.NumIn())
+	for i := range arguments {
+		arguments[i] = fmt.Sprintf("argument%d", i+1)
+	}
+
+	return NewBuiltinFunction(arguments, func(context *Context) *RuntimeResult {
+		res := NewRuntimeResult()
+
+		in := make([]reflect.Value, len(arguments))
+		for i, argname := range arguments {
+			argument, err := toGo(context.table.Get(argname), functionType.In(i))
+			if err != nil {
+				return res.Failure(NewRuntimeError(
+					fmt.Sprintf("Argument %d: %s", i+1, err),
+					context.entry, context.entry, context,
+				))
+			}
+			in[i] = argument
+		}
+
+		out := callable.Call(in)
+
+		if returnsError {
+			if err := out[len(out)-1]; !err.IsNil() {
+				return res.Failure(NewRuntimeError(
+					err.Interface().(error).Error(), context.entry, context.entry, context,
+				))
+			}
+			out = out[:len(out)-1]
+		}
+
+		if len(out) == 0 {
+			return res.Success(nil)
+		}
+
+		value, err := fromGo(out[0])
+		if err != nil {
+			return res.Failure(NewRuntimeError(
+				fmt.Sprintf("Return value: %s", err), context.entry, context.entry, context,
+			))
+		}
+		if value != nil {
+			value = value.SetContext(context)
+		}
+		return res.Success(value)
+	}), nil
+}
+
+func (self *Interpreter) Register(name string, function interface{}) error {
+	builtin, err := NewHostFunction(function)
+	if err != nil {
+		return err
+	}
+	self.Set(name, builtin)
+	return nil
+}
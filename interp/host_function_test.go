@@ -0,0 +1,74 @@
+package interp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	interpreter := NewInterpreter(Options{})
+
+	err := interpreter.Register("repeat", func(s string, n int) (string, error) {
+		if n < 0 {
+			return "", errors.New("n must not be negative")
+		}
+		return strings.Repeat(s, n), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = interpreter.Register("sum", func(values []float64) float64 {
+		res := 0.0
+		for _, value := range values {
+			res += value
+		}
+		return res
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := interpreter.Run("<test>", `[repeat("ab", 2), sum([1, 2, 3])]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.String() != "[abab, 6]" {
+		t.Errorf("got %s", value)
+	}
+
+	tests := []struct {
+		text, details string
+	}{
+		{`repeat("ab", -1)`, "n must not be negative"},
+		{`repeat(1, 2)`, "Argument 1: expected string, got number"},
+		{`repeat("ab", 1.5)`, "Argument 2: expected whole number"},
+		{`sum([1, "a"])`, "Argument 1: item 1: expected number, got string"},
+		{`repeat("ab")`, "1 too few arguments"},
+	}
+	for _, test := range tests {
+		_, err := interpreter.Run("<test>", test.text)
+		if err == nil {
+			t.Errorf("%s: expected an error", test.text)
+			continue
+		}
+		if !strings.Contains(err.(*Error).Details(), test.details) {
+			t.Errorf("%s: details %q do not contain %q", test.text, err.(*Error).Details(), test.details)
+		}
+	}
+}
+
+func TestRegisterRejectsUnsupportedFunctions(t *testing.T) {
+	interpreter := NewInterpreter(Options{})
+
+	for _, function := range []interface{}{
+		42,
+		func(values ...int) {},
+		func() (int, int) { return 0, 0 },
+		func() (int, error, error) { return 0, nil, nil },
+	} {
+		if err := interpreter.Register("f", function); err == nil {
+			t.Errorf("expected an error for %T", function)
+		}
+	}
+}
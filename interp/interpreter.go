@@ -0,0 +1,785 @@
+package interp
+
+import (
+	"fmt"
+)
+
+func (self WrongNode) Interpret(context *Context) *RuntimeResult {
+	panic("No visit method for WrongNode")
+}
+
+func (self *NumberNode) Interpret(context *Context) *RuntimeResult {
+	return NewRuntimeResult().Success(
+		NewNumber(
+			self.token.value.(float64),
+		).SetContext(context).SetPosition(self.Start(), self.End()),
+	)
+}
+
+func (self *StringNode) Interpret(context *Context) *RuntimeResult {
+	return NewRuntimeResult().Success(
+		NewString(
+			self.token.value.(string),
+		).SetContext(context).SetPosition(self.Start(), self.End()),
+	)
+}
+
+func (self *ListNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	// Named functions are defined before the other statements of the block run, so they can be
+	// called before their definition and from each other. Functions with default values are
+	// defined again where they are written, once the variables their defaults use exist.
+	hoisted := make(map[int]Value)
+	if self.statements {
+		for i, value := range self.values {
+			if definition, ok := value.(*FunctionDefinitionNode); ok && definition.name != nil {
+				function := definition.declare(context)
+				if len(definition.defaults) == 0 {
+					hoisted[i] = function
+				}
+			}
+		}
+	}
+
+	var values []Value
+	for i, value := range self.values {
+		if function, ok := hoisted[i]; ok {
+			values = append(values, function)
+			continue
+		}
+		values = append(values, res.Register(value.Interpret(context)))
+		if res.ShouldReturn() {
+			return res
+		}
+	}
+
+	if self.statements {
+		if len(values) > 0 {
+			return res.Success(values[len(values)-1])
+		}
+		return res.Success(nil)
+	}
+
+	list := NewList(values)
+	if err := context.allocate(float64(len(list.values)), self.Start(), self.End()); err != nil {
+		return res.Failure(err)
+	}
+	return res.Success(list.SetContext(context).SetPosition(self.Start(), self.End()))
+}
+
+func (self *MapNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	result := NewMap().SetContext(context).SetPosition(self.Start(), self.End()).(*Map)
+	for i, keyNode := range self.keys {
+		key := res.Register(keyNode.Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+		value := res.Register(self.values[i].Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+
+		if key == nil {
+			return res.Failure(NewRuntimeError(
+				"Map keys must be numbers or strings, not nothing",
+				keyNode.Start(), keyNode.End(), context,
+			))
+		}
+		if value == nil {
+			continue
+		}
+		if err := result.SetIndex(key, value); err != nil {
+			return res.Failure(err)
+		}
+	}
+
+	return res.Success(result)
+}
+
+func (self *BinaryOperationNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	left := res.Register(self.left.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	right := res.Register(self.right.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	if left == nil {
+		return res.Failure(NewRuntimeError(
+			"Can not use nothing in an operation", self.left.Start(), self.left.End(), context,
+		))
+	}
+	if right == nil {
+		return res.Failure(NewRuntimeError(
+			"Can not use nothing in an operation", self.right.Start(), self.right.End(), context,
+		))
+	}
+
+	var value Value
+	var err *Error
+
+	switch self.operation.tokenType {
+	case PLUS:
+		value, err = left.Add(right)
+	case MINUS:
+		value, err = left.Subtract(right)
+	case MUL:
+		value, err = left.Multiply(right)
+	case DIV:
+		value, err = left.Divide(right)
+	case MOD:
+		value, err = left.Modulo(right)
+	case POW:
+		value, err = left.Pow(right)
+	case AND:
+		value, err = left.And(right)
+	case OR:
+		value, err = left.Or(right)
+	case EE:
+		value, err = left.Equals(right)
+	case NE:
+		value, err = left.NotEquals(right)
+	case LT:
+		value, err = left.LessThan(right)
+	case GT:
+		value, err = left.GreaterThan(right)
+	case LE:
+		value, err = left.LessEquals(right)
+	case GE:
+		value, err = left.GreaterEquals(right)
+	}
+
+	if err != nil {
+		return res.Failure(err)
+	}
+
+	return res.Success(value.SetPosition(self.Start(), self.End()))
+}
+
+func (self *UnaryOperationNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	left := res.Register(self.node.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+	if left == nil {
+		return res.Failure(NewRuntimeError(
+			"Can not use nothing in an operation", self.node.Start(), self.node.End(), context,
+		))
+	}
+
+	var value Value
+	var err *Error
+
+	switch self.operation.tokenType {
+	case PLUS:
+		value = left
+	case MINUS:
+		value, err = left.Multiply(NewNumber(-1))
+	case NOT:
+		value, err = left.Not()
+	}
+
+	if err != nil {
+		return res.Failure(err)
+	}
+
+	return res.Success(value.SetPosition(self.Start(), self.End()))
+}
+
+func (self *VariableAssignmentNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	varname := self.name.value.(string)
+	value := res.Register(self.node.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	stored, err := context.keep(value, self.Start(), self.End())
+	if err != nil {
+		return res.Failure(err)
+	}
+	context.table.Set(varname, stored)
+
+	return res.Success(value)
+}
+
+func (self *VariableUpdateNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	varname := self.name.value.(string)
+	value := res.Register(self.node.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	stored, err := context.keep(value, self.Start(), self.End())
+	if err != nil {
+		return res.Failure(err)
+	}
+	if !context.table.Update(varname, stored) {
+		return res.Failure(NewRuntimeError(
+			fmt.Sprintf("'%s' is not defined, use 'var %s = ...' to declare it", varname, varname),
+			self.name.start, self.name.end, context,
+		))
+	}
+
+	return res.Success(value)
+}
+
+func (self *ScopeNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	for _, name := range self.names {
+		varname := name.value.(string)
+		if self.keyword == "global" {
+			context.table.Global(varname)
+		} else if !context.table.Nonlocal(varname) {
+			return res.Failure(NewRuntimeError(
+				fmt.Sprintf("'%s' is not defined in an enclosing scope", varname),
+				name.start, name.end, context,
+			))
+		}
+	}
+
+	return res.Success(nil)
+}
+
+func (self *VariableAccessNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	varname := self.name.value.(string)
+	value := context.table.Get(varname)
+	if value == nil {
+		return res.Failure(NewRuntimeError(
+			fmt.Sprintf("'%s' is not defined", varname),
+			self.Start(), self.End(), context,
+		))
+	}
+
+	value = view(value).SetPosition(self.Start(), self.End()).SetContext(context)
+	return res.Success(value)
+}
+
+func (self *IfNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	for _, pair := range self.cases {
+		condition := pair[0]
+		expression := pair[1]
+
+		conditionValue := res.Register(condition.Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+
+		check := conditionValue.IsTrue()
+
+		if check {
+			expressionValue := res.Register(expression.Interpret(context))
+			if res.ShouldReturn() {
+				return res
+			}
+			return res.Success(expressionValue)
+		}
+	}
+
+	if self.elseCase != nil {
+		elseValue := res.Register(self.elseCase.Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+		return res.Success(elseValue)
+	}
+	return res.Success(nil)
+}
+
+func (self *ForNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	var values []Value
+
+	from := res.Register(self.from.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+	to := res.Register(self.to.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	var step Value
+	if self.step != nil {
+		step = res.Register(self.step.Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+	} else {
+		step = NewNumber(1)
+	}
+
+	var condition func() (bool, *Error)
+	ascending, err := step.GreaterEquals(NewNumber(0))
+	if err != nil {
+		return res.Failure(err)
+	}
+	if ascending.IsTrue() {
+		condition = func() (bool, *Error) {
+			check, err := from.LessThan(to)
+			if err != nil {
+				return false, err
+			}
+			return check.IsTrue(), nil
+		}
+	} else {
+		condition = func() (bool, *Error) {
+			check, err := from.GreaterThan(to)
+			if err != nil {
+				return false, err
+			}
+			return check.IsTrue(), nil
+		}
+	}
+
+	for {
+		if err := context.checkpoint(self.Start(), self.End()); err != nil {
+			return res.Failure(err)
+		}
+
+		check, err := condition()
+		if err != nil {
+			return res.Failure(err)
+		}
+		if !check {
+			break
+		}
+
+		context.table.Set(self.varname.value.(string), from.Copy())
+		from, err = from.Add(step)
+		if err != nil {
+			return res.Failure(err)
+		}
+
+		value := res.Register(self.body.Interpret(context))
+		if res.ShouldReturn() && !res.shouldContinue && !res.shouldBreak {
+			return res
+		}
+
+		if res.shouldContinue {
+			continue
+		}
+		if res.shouldBreak {
+			break
+		}
+
+		if value != nil {
+			if err := context.allocate(1, self.Start(), self.End()); err != nil {
+				return res.Failure(err)
+			}
+			values = append(values, value)
+		}
+	}
+
+	return res.Success(
+		NewList(values).SetContext(context).SetPosition(self.Start(), self.End()),
+	)
+}
+
+func (self *ForEachNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	var values []Value
+
+	collection := res.Register(self.iterable.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	iterable, ok := collection.(Iterable)
+	if !ok {
+		return res.Failure(NewRuntimeError(
+			fmt.Sprintf("Can not loop over a %s", TypeName(collection)),
+			self.iterable.Start(), self.iterable.End(), context,
+		))
+	}
+	iterator := iterable.Iterate()
+
+	for {
+		if err := context.checkpoint(self.Start(), self.End()); err != nil {
+			return res.Failure(err)
+		}
+
+		key, item, ok := iterator.Next()
+		if !ok {
+			break
+		}
+
+		if self.keyname != nil {
+			context.table.Set(self.keyname.value.(string), key.Copy().SetContext(context))
+		}
+		item, err := context.keep(item, self.Start(), self.End())
+		if err != nil {
+			return res.Failure(err)
+		}
+		context.table.Set(self.varname.value.(string), item.SetContext(context))
+
+		value := res.Register(self.body.Interpret(context))
+		if res.ShouldReturn() && !res.shouldContinue && !res.shouldBreak {
+			return res
+		}
+
+		if res.shouldContinue {
+			continue
+		}
+		if res.shouldBreak {
+			break
+		}
+
+		if value != nil {
+			if err := context.allocate(1, self.Start(), self.End()); err != nil {
+				return res.Failure(err)
+			}
+			values = append(values, value)
+		}
+	}
+
+	return res.Success(
+		NewList(values).SetContext(context).SetPosition(self.Start(), self.End()),
+	)
+}
+
+func (self *WhileNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	var values []Value
+
+	for {
+		if err := context.checkpoint(self.Start(), self.End()); err != nil {
+			return res.Failure(err)
+		}
+
+		condition := res.Register(self.condition.Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+
+		if !condition.IsTrue() {
+			break
+		}
+
+		value := res.Register(self.body.Interpret(context))
+		if res.ShouldReturn() && !res.shouldContinue && !res.shouldBreak {
+			return res
+		}
+
+		if res.shouldContinue {
+			continue
+		}
+		if res.shouldBreak {
+			break
+		}
+
+		if value != nil {
+			if err := context.allocate(1, self.Start(), self.End()); err != nil {
+				return res.Failure(err)
+			}
+			values = append(values, value)
+		}
+	}
+
+	return res.Success(
+		NewList(values).SetContext(context).SetPosition(self.Start(), self.End()),
+	)
+}
+
+func (self *FunctionDefinitionNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	defaults := make([]Value, len(self.defaults))
+	for i, node := range self.defaults {
+		defaults[i] = res.Register(node.Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+		if defaults[i] == nil {
+			return res.Failure(NewRuntimeError(
+				"Default value is nothing", node.Start(), node.End(), context,
+			))
+		}
+	}
+
+	return res.Success(self.define(context, defaults))
+}
+
+// declare defines a named function before its default values are known. Calls that leave out an
+// argument with a default fail until the definition itself has run.
+func (self *FunctionDefinitionNode) declare(context *Context) Value {
+	return self.define(context, make([]Value, len(self.defaults)))
+}
+
+func (self *FunctionDefinitionNode) define(context *Context, defaults []Value) Value {
+	body := self.body
+	argnames := make([]string, len(self.arguments))
+	for i, argument := range self.arguments {
+		argnames[i] = argument.value.(string)
+	}
+
+	name := ""
+	if self.name != nil {
+		name = self.name.value.(string)
+	}
+	rest := ""
+	if self.rest != nil {
+		rest = self.rest.value.(string)
+	}
+	function := NewFunction(name, argnames, body, context.table).
+		Defaults(defaults...).
+		Rest(rest).
+		SetContext(context).
+		SetPosition(self.Start(), self.End())
+
+	if name != "" {
+		context.table.Set(name, function)
+	}
+	return function
+}
+
+func (self *FunctionCallNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	call := res.Register(self.call.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+	if _, ok := call.(BaseFunction); !ok {
+		return res.Failure(NewRuntimeError(
+			fmt.Sprintf("A %s can not be called", TypeName(call)),
+			self.call.Start(), self.call.End(), context,
+		))
+	}
+	function := call.Copy().SetPosition(self.Start(), self.End()).(BaseFunction)
+
+	var arguments []Value
+
+	for _, argument := range self.arguments {
+		arguments = append(arguments, res.Register(argument.Interpret(context)))
+		if res.ShouldReturn() {
+			return res
+		}
+	}
+
+	keywords := make(map[string]Value)
+	for i, keyword := range self.keywords {
+		keywords[keyword.value.(string)] = res.Register(self.keywordValues[i].Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+	}
+
+	if err := context.checkpoint(self.Start(), self.End()); err != nil {
+		return res.Failure(err)
+	}
+
+	value := res.Register(function.Execute(arguments, keywords))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	if value == nil {
+		return res.Success(nil)
+	}
+
+	value = view(value).SetPosition(self.Start(), self.End()).SetContext(context)
+	return res.Success(value)
+}
+
+func (self *IndexNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	value := res.Register(self.lookup(context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	value = view(value).SetPosition(self.Start(), self.End()).SetContext(context)
+	return res.Success(value)
+}
+
+func (self *IndexNode) lookup(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	collection := res.Register(reference(self.node, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	index := res.Register(self.index.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+	if index == nil {
+		return res.Failure(NewRuntimeError(
+			"Can not index with nothing", self.index.Start(), self.index.End(), context,
+		))
+	}
+
+	indexable, ok := collection.(Indexable)
+	if !ok {
+		return res.Failure(NewRuntimeError(
+			fmt.Sprintf("'[]' not supported for %s", TypeName(collection)),
+			self.node.Start(), self.node.End(), context,
+		))
+	}
+
+	value, err := indexable.Index(index)
+	if err != nil {
+		return res.Failure(err)
+	}
+	return res.Success(value)
+}
+
+func (self *SliceNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	collection := res.Register(self.node.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	var bounds [3]Value
+	for i, bound := range []Node{self.from, self.to, self.step} {
+		if bound == nil {
+			continue
+		}
+		bounds[i] = res.Register(bound.Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+	}
+
+	sliceable, ok := collection.(Sliceable)
+	if !ok {
+		return res.Failure(NewRuntimeError(
+			fmt.Sprintf("'[:]' not supported for %s", TypeName(collection)),
+			self.node.Start(), self.node.End(), context,
+		))
+	}
+
+	value, err := sliceable.Slice(bounds[0], bounds[1], bounds[2])
+	if err != nil {
+		return res.Failure(err)
+	}
+	return res.Success(value.SetPosition(self.Start(), self.End()))
+}
+
+func (self *IndexAssignmentNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	collection := res.Register(reference(self.target.node, context))
+	if res.ShouldReturn() {
+		return res
+	}
+	if !rooted(self.target.node) {
+		// Changing a value that is not stored anywhere, like the result of a call, must not change
+		// the variable it was read from.
+		collection = clone(collection)
+	}
+
+	index := res.Register(self.target.index.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+	if index == nil {
+		return res.Failure(NewRuntimeError(
+			"Can not index with nothing", self.target.index.Start(), self.target.index.End(), context,
+		))
+	}
+
+	value := res.Register(self.node.Interpret(context))
+	if res.ShouldReturn() {
+		return res
+	}
+	if value == nil {
+		return res.Failure(NewRuntimeError(
+			"Can not assign nothing to an index", self.node.Start(), self.node.End(), context,
+		))
+	}
+
+	indexable, ok := collection.(Indexable)
+	if !ok {
+		return res.Failure(NewRuntimeError(
+			fmt.Sprintf("'[]' not supported for %s", TypeName(collection)),
+			self.target.node.Start(), self.target.node.End(), context,
+		))
+	}
+
+	stored, err := context.keep(value, self.node.Start(), self.node.End())
+	if err != nil {
+		return res.Failure(err)
+	}
+	if err := indexable.SetIndex(index, stored); err != nil {
+		return res.Failure(err)
+	}
+	return res.Success(value)
+}
+
+// reference evaluates node without copying variables and indexed values, so that index
+// assignments change the value that is stored instead of a copy of it.
+func reference(node Node, context *Context) *RuntimeResult {
+	switch n := node.(type) {
+	case *VariableAccessNode:
+		value := context.table.Get(n.name.value.(string))
+		if value == nil {
+			return n.Interpret(context)
+		}
+		return NewRuntimeResult().Success(value)
+	case *IndexNode:
+		return n.lookup(context)
+	default:
+		return node.Interpret(context)
+	}
+}
+
+// rooted reports whether node is a variable or an index into one, so that reference gives the
+// stored value.
+func rooted(node Node) bool {
+	switch n := node.(type) {
+	case *VariableAccessNode:
+		return true
+	case *IndexNode:
+		return rooted(n.node)
+	default:
+		return false
+	}
+}
+
+func (self *ReturnNode) Interpret(context *Context) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	var value Value
+	if self.nodeToReturn != nil {
+		value = res.Register(self.nodeToReturn.Interpret(context))
+		if res.ShouldReturn() {
+			return res
+		}
+	}
+	return res.SuccessReturn(value)
+}
+
+func (self *ContinueNode) Interpret(context *Context) *RuntimeResult {
+	return NewRuntimeResult().SuccessContinue()
+}
+
+func (self *BreakNode) Interpret(context *Context) *RuntimeResult {
+	return NewRuntimeResult().SuccessBreak()
+}
@@ -0,0 +1,74 @@
+package interp
+
+type Iterable interface {
+	Value
+	Iterate() Iterator
+}
+
+type Iterator interface {
+	Next() (key, value Value, ok bool)
+}
+
+type sliceIterator struct {
+	values []Value
+	index  int
+}
+
+func (self *sliceIterator) Next() (Value, Value, bool) {
+	if self.index >= len(self.values) {
+		return nil, nil, false
+	}
+	key := NewNumber(float64(self.index))
+	value := self.values[self.index]
+	self.index++
+	return key, value, true
+}
+
+type stringIterator struct {
+	runes []rune
+	index int
+}
+
+func (self *stringIterator) Next() (Value, Value, bool) {
+	if self.index >= len(self.runes) {
+		return nil, nil, false
+	}
+	key := NewNumber(float64(self.index))
+	value := NewString(string(self.runes[self.index]))
+	self.index++
+	return key, value, true
+}
+
+type mapIterator struct {
+	keys   []Value
+	values map[interface{}]Value
+	index  int
+}
+
+func (self *mapIterator) Next() (Value, Value, bool) {
+	if self.index >= len(self.keys) {
+		return nil, nil, false
+	}
+	key := self.keys[self.index]
+	k, _ := mapKey(key)
+	self.index++
+	return key, self.values[k], true
+}
+
+func (self *List) Iterate() Iterator {
+	values := make([]Value, len(self.values))
+	copy(values, self.values)
+	return &sliceIterator{values, 0}
+}
+
+func (self *String) Iterate() Iterator {
+	return &stringIterator{[]rune(self.value), 0}
+}
+
+func (self *Map) Iterate() Iterator {
+	values := make(map[interface{}]Value, len(self.values))
+	for k, value := range self.values {
+		values[k] = value
+	}
+	return &mapIterator{append([]Value(nil), self.keys...), values, 0}
+}
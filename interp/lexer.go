@@ -1,4 +1,4 @@
-package main
+package interp
 
 import (
 	"strconv"
@@ -34,12 +34,18 @@ func (self *Lexer) Advance() {
 func (self *Lexer) MakeTokens() ([]*Token, *Error) {
 	var tokens []*Token
 
+	if strings.HasPrefix(self.text, "#!") {
+		self.SkipShebang()
+	}
+
 	for self.current != -1 {
 		switch self.current {
 		case ' ', '\t':
 			self.Advance()
 		case '#':
-			self.SkipComment()
+			if err := self.SkipComment(); err != nil {
+				return nil, err
+			}
 		case '+':
 			tokens = append(tokens, NewToken(PLUS, nil, self.position, nil))
 			self.Advance()
@@ -70,9 +76,24 @@ func (self *Lexer) MakeTokens() ([]*Token, *Error) {
 		case ']':
 			tokens = append(tokens, NewToken(RBRACKET, nil, self.position, nil))
 			self.Advance()
+		case '{':
+			tokens = append(tokens, NewToken(LBRACE, nil, self.position, nil))
+			self.Advance()
+		case '}':
+			tokens = append(tokens, NewToken(RBRACE, nil, self.position, nil))
+			self.Advance()
 		case ',':
 			tokens = append(tokens, NewToken(COMMA, nil, self.position, nil))
 			self.Advance()
+		case ':':
+			tokens = append(tokens, NewToken(COLON, nil, self.position, nil))
+			self.Advance()
+		case '.':
+			token, err := self.MakeEllipsis()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
 		case '\n', ';':
 			tokens = append(tokens, NewToken(NEWLINE, nil, self.position, nil))
 			self.Advance()
@@ -85,7 +106,11 @@ func (self *Lexer) MakeTokens() ([]*Token, *Error) {
 		case '>':
 			tokens = append(tokens, self.MakeGreater())
 		case '"':
-			tokens = append(tokens, self.MakeString())
+			token, err := self.MakeString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
 		case '|':
 			token, err := self.MakeOr()
 			if err != nil {
@@ -222,8 +247,23 @@ func (self *Lexer) MakeAnd() (*Token, *Error) {
 	self.Advance()
 	return NewToken(AND, nil, start, self.position), nil
 }
-func (self *Lexer) MakeString() *Token {
-	res := ""
+
+func (self *Lexer) MakeEllipsis() (*Token, *Error) {
+	start := self.position.Copy()
+
+	for i := 0; i < 3; i++ {
+		if self.current != '.' {
+			return nil, NewExpectedCharacterError("'...'", start, self.position)
+		}
+		self.Advance()
+	}
+
+	return NewToken(ELLIPSIS, nil, start, self.position), nil
+}
+
+func (self *Lexer) MakeString() (*Token, *Error) {
+	// The text is read byte by byte, so characters outside of ASCII are copied as their bytes.
+	var res []byte
 	start := self.position.Copy()
 	escape := false
 
@@ -240,26 +280,30 @@ func (self *Lexer) MakeString() *Token {
 		if escape {
 			next := escape_characters[self.current]
 			if next == "" {
-				res += string(self.current)
+				res = append(res, byte(self.current))
 			} else {
-				res += next
+				res = append(res, next...)
 			}
 			escape = false
 		} else {
 			if self.current == '\\' {
 				escape = true
 			} else {
-				res += string(self.current)
+				res = append(res, byte(self.current))
 			}
 		}
 		self.Advance()
 	}
 
+	if self.current == -1 {
+		return nil, NewExpectedCharacterError("'\"'", self.position, self.position)
+	}
+
 	self.Advance()
-	return NewToken(STRING, res, start, self.position)
+	return NewToken(STRING, string(res), start, self.position), nil
 }
 
-func (self *Lexer) SkipComment() {
+func (self *Lexer) SkipComment() *Error {
 	self.Advance()
 	block := false
 
@@ -274,15 +318,27 @@ func (self *Lexer) SkipComment() {
 				self.Advance()
 				if self.current == '#' {
 					self.Advance()
-					break
+					return nil
 				}
+				continue
 			}
 		} else {
 			if self.current == '\n' || self.current == ';' {
 				self.Advance()
-				break
+				return nil
 			}
 		}
 		self.Advance()
 	}
+
+	if block {
+		return NewExpectedCharacterError("'=#'", self.position, self.position)
+	}
+	return nil
+}
+
+func (self *Lexer) SkipShebang() {
+	for self.current != -1 && self.current != '\n' {
+		self.Advance()
+	}
 }
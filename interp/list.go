@@ -1,4 +1,4 @@
-package main
+package interp
 
 import (
 	"fmt"
@@ -70,6 +70,10 @@ func (self *List) End() *Position {
 }
 
 func (self *List) Add(value Value) (Value, *Error) {
+	err := self.context.allocate(float64(len(self.values)+1), self.Start(), value.End())
+	if err != nil {
+		return nil, err
+	}
 	res := self.Copy().(*List)
 	res.values = append(res.values, value)
 	return res, nil
@@ -106,6 +110,12 @@ func (self *List) Subtract(value Value) (Value, *Error) {
 func (self *List) Multiply(value Value) (Value, *Error) {
 	switch v := value.(type) {
 	case *List:
+		err := self.context.allocate(
+			float64(len(self.values)+len(v.values)), self.Start(), value.End(),
+		)
+		if err != nil {
+			return nil, err
+		}
 		res := self.Copy().(*List)
 		res.values = append(res.values, v.values...)
 		return res, nil
@@ -164,6 +174,12 @@ func (self *List) Pow(value Value) (Value, *Error) {
 				self.Start(), value.End(), self.context,
 			)
 		}
+		err := self.context.allocate(
+			float64(len(self.values))*v.value, self.Start(), value.End(),
+		)
+		if err != nil {
+			return nil, err
+		}
 		res := self.Copy().(*List)
 		res.values = repeat(res.values, vint)
 		return res, nil
@@ -278,3 +294,36 @@ func (self *List) Not() (*Number, *Error) {
 func (self *List) IsTrue() bool {
 	return len(self.values) > 0
 }
+
+func (self *List) Index(index Value) (Value, *Error) {
+	i, err := toIndex(index, len(self.values), self.context)
+	if err != nil {
+		return nil, err
+	}
+	return self.values[i], nil
+}
+
+func (self *List) SetIndex(index, value Value) *Error {
+	i, err := toIndex(index, len(self.values), self.context)
+	if err != nil {
+		return err
+	}
+	self.values[i] = value
+	return nil
+}
+
+func (self *List) Slice(start, end, step Value) (Value, *Error) {
+	indices, err := sliceIndices(len(self.values), start, end, step, self.context)
+	if err != nil {
+		return nil, err
+	}
+	if err := self.context.allocate(float64(len(indices)), self.Start(), self.End()); err != nil {
+		return nil, err
+	}
+
+	values := make([]Value, len(indices))
+	for i, index := range indices {
+		values[i] = self.values[index]
+	}
+	return NewList(values).SetContext(self.context), nil
+}
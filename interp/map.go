@@ -0,0 +1,324 @@
+package interp
+
+import (
+	"fmt"
+)
+
+type Map struct {
+	keys       []Value
+	values     map[interface{}]Value
+	start, end *Position
+	context    *Context
+}
+
+func NewMap() *Map {
+	return &Map{nil, make(map[interface{}]Value), nil, nil, nil}
+}
+
+func mapKey(key Value) (interface{}, bool) {
+	switch k := key.(type) {
+	case *Number:
+		return k.value, true
+	case *String:
+		return k.value, true
+	default:
+		return nil, false
+	}
+}
+
+func (self *Map) keyError(key Value) *Error {
+	start, end := self.start, self.end
+	if key != nil {
+		start, end = key.Start(), key.End()
+	}
+	return NewRuntimeError(
+		fmt.Sprintf("Map keys must be numbers or strings, not %s", TypeName(key)),
+		start, end, self.context,
+	)
+}
+
+func (self *Map) get(key Value) (Value, bool) {
+	k, ok := mapKey(key)
+	if !ok {
+		return nil, false
+	}
+	value, ok := self.values[k]
+	return value, ok
+}
+
+func (self *Map) set(key, value Value) bool {
+	k, ok := mapKey(key)
+	if !ok {
+		return false
+	}
+	if value == nil {
+		return true
+	}
+	if _, ok := self.values[k]; !ok {
+		self.keys = append(self.keys, key.Copy())
+	}
+	self.values[k] = value
+	return true
+}
+
+func (self *Map) size() int {
+	return len(self.keys)
+}
+
+func (self *Map) String() string {
+	res := "{"
+	for i, key := range self.keys {
+		if i > 0 {
+			res += ", "
+		}
+		k, _ := mapKey(key)
+		res += fmt.Sprintf("%s: %s", key.String(), self.values[k].String())
+	}
+	res += "}"
+	return res
+}
+
+func (self *Map) Copy() Value {
+	res := NewMap()
+	for _, key := range self.keys {
+		k, _ := mapKey(key)
+		res.set(key, self.values[k])
+	}
+	res.SetPosition(self.start, self.end)
+	res.SetContext(self.context)
+	return res
+}
+
+func (self *Map) SetPosition(start, end *Position) Value {
+	if start == nil {
+		self.start = nil
+	} else {
+		self.start = start.Copy()
+	}
+	if end == nil {
+		self.end = nil
+	} else {
+		self.end = end.Copy()
+	}
+	return self
+}
+
+func (self *Map) SetContext(context *Context) Value {
+	self.context = context
+	return self
+}
+
+func (self *Map) Start() *Position {
+	return self.start
+}
+
+func (self *Map) End() *Position {
+	return self.end
+}
+
+func (self *Map) Add(value Value) (Value, *Error) {
+	switch v := value.(type) {
+	case *Map:
+		err := self.context.allocate(float64(self.size()+v.size()), self.Start(), value.End())
+		if err != nil {
+			return nil, err
+		}
+		res := self.Copy().(*Map)
+		for _, key := range v.keys {
+			item, _ := v.get(key)
+			res.set(key, item)
+		}
+		return res, nil
+	default:
+		return nil, NewRuntimeError(
+			"'+' not supported for map and type", self.Start(), value.End(), self.context,
+		)
+	}
+}
+
+func (self *Map) Subtract(value Value) (Value, *Error) {
+	k, ok := mapKey(value)
+	if !ok {
+		return nil, self.keyError(value)
+	}
+	if _, ok := self.values[k]; !ok {
+		return nil, NewRuntimeError(
+			fmt.Sprintf("Key '%s' not found", value), self.Start(), value.End(), self.context,
+		)
+	}
+
+	res := NewMap()
+	for _, key := range self.keys {
+		if other, _ := mapKey(key); other != k {
+			res.set(key, self.values[other])
+		}
+	}
+	res.SetPosition(self.start, self.end)
+	res.SetContext(self.context)
+	return res, nil
+}
+
+func (self *Map) Multiply(value Value) (Value, *Error) {
+	return nil, NewRuntimeError(
+		"'*' not supported for map", self.Start(), value.End(), self.context,
+	)
+}
+
+func (self *Map) Divide(value Value) (Value, *Error) {
+	if _, ok := mapKey(value); !ok {
+		return nil, self.keyError(value)
+	}
+	item, ok := self.get(value)
+	if !ok {
+		return nil, NewRuntimeError(
+			fmt.Sprintf("Key '%s' not found", value), self.Start(), value.End(), self.context,
+		)
+	}
+	return item, nil
+}
+
+func (self *Map) Modulo(value Value) (Value, *Error) {
+	return nil, NewRuntimeError(
+		"'%' not supported for map", self.Start(), value.End(), self.context,
+	)
+}
+
+func (self *Map) Pow(value Value) (Value, *Error) {
+	return nil, NewRuntimeError(
+		"'^' not supported for map", self.Start(), value.End(), self.context,
+	)
+}
+
+func (self *Map) Equals(value Value) (*Number, *Error) {
+	switch v := value.(type) {
+	case *Map:
+		if self.size() != v.size() {
+			return NewNumber(0).SetContext(self.context).(*Number), nil
+		}
+
+		for _, key := range self.keys {
+			selfValue, _ := self.get(key)
+			otherValue, ok := v.get(key)
+			if !ok {
+				return NewNumber(0).SetContext(self.context).(*Number), nil
+			}
+			check, err := selfValue.Equals(otherValue)
+			if err != nil {
+				return nil, err
+			}
+			if check.value == 0 {
+				return NewNumber(0).SetContext(self.context).(*Number), nil
+			}
+		}
+		return NewNumber(1).SetContext(self.context).(*Number), nil
+	default:
+		return nil, NewRuntimeError(
+			"'==' not supported between map and type", self.Start(), value.End(), self.context,
+		)
+	}
+}
+
+func (self *Map) NotEquals(value Value) (*Number, *Error) {
+	switch v := value.(type) {
+	case *Map:
+		var res float64
+		check, err := self.Equals(v)
+		if err != nil {
+			return nil, err
+		}
+		if check.value == 0 {
+			res = 1
+		} else {
+			res = 0
+		}
+		return NewNumber(res).SetContext(self.context).(*Number), nil
+	default:
+		return nil, NewRuntimeError(
+			"'!=' not supported between map and type", self.Start(), value.End(), self.context,
+		)
+	}
+}
+
+func (self *Map) LessThan(value Value) (*Number, *Error) {
+	return nil, NewRuntimeError(
+		"'<' not supported for map", self.Start(), value.End(), self.context,
+	)
+}
+
+func (self *Map) GreaterThan(value Value) (*Number, *Error) {
+	return nil, NewRuntimeError(
+		"'>' not supported for map", self.Start(), value.End(), self.context,
+	)
+}
+
+func (self *Map) LessEquals(value Value) (*Number, *Error) {
+	return nil, NewRuntimeError(
+		"'<=' not supported for map", self.Start(), value.End(), self.context,
+	)
+}
+
+func (self *Map) GreaterEquals(value Value) (*Number, *Error) {
+	return nil, NewRuntimeError(
+		"'>=' not supported for map", self.Start(), value.End(), self.context,
+	)
+}
+
+func (self *Map) And(value Value) (*Number, *Error) {
+	var res float64
+	if self.IsTrue() && value.IsTrue() {
+		res = 1
+	} else {
+		res = 0
+	}
+	return NewNumber(res), nil
+}
+
+func (self *Map) Or(value Value) (*Number, *Error) {
+	var res float64
+	if self.IsTrue() || value.IsTrue() {
+		res = 1
+	} else {
+		res = 0
+	}
+	return NewNumber(res), nil
+}
+
+func (self *Map) Not() (*Number, *Error) {
+	var res float64
+	if self.IsTrue() {
+		res = 0
+	} else {
+		res = 1
+	}
+	return NewNumber(res), nil
+}
+
+func (self *Map) IsTrue() bool {
+	return self.size() > 0
+}
+
+func (self *Map) Index(index Value) (Value, *Error) {
+	if _, ok := mapKey(index); !ok {
+		return nil, self.keyError(index)
+	}
+	item, ok := self.get(index)
+	if !ok {
+		return nil, NewRuntimeError(
+			fmt.Sprintf("Key '%s' not found", index), index.Start(), index.End(), self.context,
+		)
+	}
+	return item, nil
+}
+
+func (self *Map) SetIndex(index, value Value) *Error {
+	if _, ok := mapKey(index); !ok {
+		return self.keyError(index)
+	}
+	if _, ok := self.get(index); !ok {
+		if err := self.context.allocate(1, index.Start(), index.End()); err != nil {
+			return err
+		}
+	}
+	self.set(index, value)
+	return nil
+}
@@ -1,4 +1,4 @@
-package main
+package interp
 
 import (
 	"fmt"
@@ -109,6 +109,37 @@ func (self *ListNode) End() *Position {
 
 //--------------------------------------------------------------------------------------------------
 
+type MapNode struct {
+	keys, values []Node
+	start, end   *Position
+}
+
+func NewMapNode(keys, values []Node, start, end *Position) *MapNode {
+	return &MapNode{keys, values, start, end}
+}
+
+func (self *MapNode) String() string {
+	res := "{"
+	for i, key := range self.keys {
+		if i > 0 {
+			res += ", "
+		}
+		res += fmt.Sprintf("%s: %s", key.String(), self.values[i].String())
+	}
+	res += "}"
+	return res
+}
+
+func (self *MapNode) Start() *Position {
+	return self.start
+}
+
+func (self *MapNode) End() *Position {
+	return self.end
+}
+
+//--------------------------------------------------------------------------------------------------
+
 type BinaryOperationNode struct {
 	left, right Node
 	operation   *Token
@@ -180,6 +211,61 @@ func (self *VariableAssignmentNode) End() *Position {
 
 //--------------------------------------------------------------------------------------------------
 
+type VariableUpdateNode struct {
+	name *Token
+	node Node
+}
+
+func NewVariableUpdateNode(name *Token, node Node) *VariableUpdateNode {
+	return &VariableUpdateNode{name, node}
+}
+
+func (self *VariableUpdateNode) String() string {
+	return fmt.Sprintf("(%s = %s)", self.name.String(), self.node.String())
+}
+
+func (self *VariableUpdateNode) Start() *Position {
+	return self.name.start
+}
+
+func (self *VariableUpdateNode) End() *Position {
+	return self.node.End()
+}
+
+//--------------------------------------------------------------------------------------------------
+
+type ScopeNode struct {
+	keyword    string
+	names      []*Token
+	start, end *Position
+}
+
+func NewScopeNode(keyword string, names []*Token, start, end *Position) *ScopeNode {
+	return &ScopeNode{keyword, names, start, end}
+}
+
+func (self *ScopeNode) String() string {
+	res := fmt.Sprintf("(%s ", self.keyword)
+	for i, name := range self.names {
+		if i > 0 {
+			res += ", "
+		}
+		res += name.String()
+	}
+	res += ")"
+	return res
+}
+
+func (self *ScopeNode) Start() *Position {
+	return self.start
+}
+
+func (self *ScopeNode) End() *Position {
+	return self.end
+}
+
+//--------------------------------------------------------------------------------------------------
+
 type VariableAccessNode struct {
 	name *Token
 }
@@ -269,6 +355,39 @@ func (self *ForNode) End() *Position {
 
 //--------------------------------------------------------------------------------------------------
 
+type ForEachNode struct {
+	keyname, varname *Token
+	iterable         Node
+	body             Node
+}
+
+func NewForEachNode(keyname, varname *Token, iterable, body Node) *ForEachNode {
+	return &ForEachNode{keyname, varname, iterable, body}
+}
+
+func (self *ForEachNode) String() string {
+	names := self.varname.String()
+	if self.keyname != nil {
+		names = fmt.Sprintf("%s, %s", self.keyname.String(), names)
+	}
+	return fmt.Sprintf(
+		"(for %s in %s do %s end)", names, self.iterable.String(), self.body.String(),
+	)
+}
+
+func (self *ForEachNode) Start() *Position {
+	if self.keyname != nil {
+		return self.keyname.start
+	}
+	return self.varname.start
+}
+
+func (self *ForEachNode) End() *Position {
+	return self.body.End()
+}
+
+//--------------------------------------------------------------------------------------------------
+
 type WhileNode struct {
 	condition, body Node
 }
@@ -292,30 +411,51 @@ func (self *WhileNode) End() *Position {
 //--------------------------------------------------------------------------------------------------
 
 type FunctionDefinitionNode struct {
+	name      *Token
 	arguments []*Token
+	defaults  []Node
+	rest      *Token
 	body      Node
 }
 
-func NewFunctionDefinitionNode(arguments []*Token, body Node) *FunctionDefinitionNode {
-	return &FunctionDefinitionNode{arguments, body}
+func NewFunctionDefinitionNode(name *Token, arguments []*Token, defaults []Node, rest *Token,
+	body Node) *FunctionDefinitionNode {
+	return &FunctionDefinitionNode{name, arguments, defaults, rest, body}
 }
 
 func (self *FunctionDefinitionNode) String() string {
 	res := "(function ("
-	if len(self.arguments) > 1 {
-		for _, argument := range self.arguments[0 : len(self.arguments)-1] {
-			res += fmt.Sprintf("%s, ", argument.String())
+	if self.name != nil {
+		res = fmt.Sprintf("(function %s (", self.name.String())
+	}
+	required := len(self.arguments) - len(self.defaults)
+	for i, argument := range self.arguments {
+		if i > 0 {
+			res += ", "
+		}
+		res += argument.String()
+		if i >= required {
+			res += fmt.Sprintf(" = %s", self.defaults[i-required].String())
 		}
 	}
-	if len(self.arguments) > 0 {
-		res += self.arguments[len(self.arguments)-1].String()
+	if self.rest != nil {
+		if len(self.arguments) > 0 {
+			res += ", "
+		}
+		res += self.rest.String() + "..."
 	}
 	res += "))"
 	return res
 }
 
 func (self *FunctionDefinitionNode) Start() *Position {
+	if self.name != nil {
+		return self.name.start
+	}
 	if len(self.arguments) == 0 {
+		if self.rest != nil {
+			return self.rest.start
+		}
 		return self.body.Start()
 	}
 	return self.arguments[0].start
@@ -328,23 +468,30 @@ func (self *FunctionDefinitionNode) End() *Position {
 //--------------------------------------------------------------------------------------------------
 
 type FunctionCallNode struct {
-	call      Node
-	arguments []Node
+	call          Node
+	arguments     []Node
+	keywords      []*Token
+	keywordValues []Node
 }
 
-func NewFunctionCallNode(call Node, arguments []Node) *FunctionCallNode {
-	return &FunctionCallNode{call, arguments}
+func NewFunctionCallNode(call Node, arguments []Node, keywords []*Token,
+	keywordValues []Node) *FunctionCallNode {
+	return &FunctionCallNode{call, arguments, keywords, keywordValues}
 }
 
 func (self *FunctionCallNode) String() string {
 	res := fmt.Sprintf("(%s (", self.call.String())
-	if len(self.arguments) > 1 {
-		for _, argument := range self.arguments[0 : len(self.arguments)-1] {
-			res += fmt.Sprintf("%s, ", argument.String())
+	for i, argument := range self.arguments {
+		if i > 0 {
+			res += ", "
 		}
+		res += argument.String()
 	}
-	if len(self.arguments) > 0 {
-		res += self.arguments[len(self.arguments)-1].String()
+	for i, keyword := range self.keywords {
+		if i > 0 || len(self.arguments) > 0 {
+			res += ", "
+		}
+		res += fmt.Sprintf("%s = %s", keyword.String(), self.keywordValues[i].String())
 	}
 	res += "))"
 	return res
@@ -355,6 +502,9 @@ func (self *FunctionCallNode) Start() *Position {
 }
 
 func (self *FunctionCallNode) End() *Position {
+	if len(self.keywordValues) > 0 {
+		return self.keywordValues[len(self.keywordValues)-1].End()
+	}
 	if len(self.arguments) == 0 {
 		return self.call.End()
 	}
@@ -363,6 +513,88 @@ func (self *FunctionCallNode) End() *Position {
 
 //--------------------------------------------------------------------------------------------------
 
+type IndexNode struct {
+	node, index Node
+	end         *Position
+}
+
+func NewIndexNode(node, index Node, end *Position) *IndexNode {
+	return &IndexNode{node, index, end}
+}
+
+func (self *IndexNode) String() string {
+	return fmt.Sprintf("(%s [%s])", self.node.String(), self.index.String())
+}
+
+func (self *IndexNode) Start() *Position {
+	return self.node.Start()
+}
+
+func (self *IndexNode) End() *Position {
+	return self.end
+}
+
+//--------------------------------------------------------------------------------------------------
+
+type SliceNode struct {
+	node           Node
+	from, to, step Node
+	end            *Position
+}
+
+func NewSliceNode(node, from, to, step Node, end *Position) *SliceNode {
+	return &SliceNode{node, from, to, step, end}
+}
+
+func (self *SliceNode) String() string {
+	res := fmt.Sprintf("(%s [", self.node.String())
+	if self.from != nil {
+		res += self.from.String()
+	}
+	res += ":"
+	if self.to != nil {
+		res += self.to.String()
+	}
+	if self.step != nil {
+		res += ":" + self.step.String()
+	}
+	res += "])"
+	return res
+}
+
+func (self *SliceNode) Start() *Position {
+	return self.node.Start()
+}
+
+func (self *SliceNode) End() *Position {
+	return self.end
+}
+
+//--------------------------------------------------------------------------------------------------
+
+type IndexAssignmentNode struct {
+	target *IndexNode
+	node   Node
+}
+
+func NewIndexAssignmentNode(target *IndexNode, node Node) *IndexAssignmentNode {
+	return &IndexAssignmentNode{target, node}
+}
+
+func (self *IndexAssignmentNode) String() string {
+	return fmt.Sprintf("(%s = %s)", self.target.String(), self.node.String())
+}
+
+func (self *IndexAssignmentNode) Start() *Position {
+	return self.target.Start()
+}
+
+func (self *IndexAssignmentNode) End() *Position {
+	return self.node.End()
+}
+
+//--------------------------------------------------------------------------------------------------
+
 type ReturnNode struct {
 	nodeToReturn Node
 	start, end   *Position
@@ -1,4 +1,4 @@
-package main
+package interp
 
 import (
 	"math"
@@ -94,7 +94,17 @@ func (self *Number) Multiply(value Value) (Value, *Error) {
 				self.Start(), value.End(), self.context,
 			)
 		}
-		return NewString(strings.Repeat(v.value, int(self.value))), nil
+		if self.value < 0 {
+			return nil, NewRuntimeError(
+				"'*' not supported for negative number and string",
+				self.Start(), value.End(), self.context,
+			)
+		}
+		err := self.context.allocate(float64(len(v.value))*self.value, self.Start(), value.End())
+		if err != nil {
+			return nil, err
+		}
+		return NewString(strings.Repeat(v.value, int(self.value))).SetContext(self.context), nil
 	default:
 		return nil, NewRuntimeError(
 			"'*' not supported between number and type", self.Start(), value.End(), self.context,
@@ -1,7 +1,8 @@
-package main
+package interp
 
 type ParseResult struct {
 	error            *Error
+	tryError         *Error
 	node             Node
 	lastAdvanceCount int
 	advanceCount     int
@@ -9,7 +10,7 @@ type ParseResult struct {
 }
 
 func NewParseResult() *ParseResult {
-	return &ParseResult{nil, NewWrongNode(), 0, 0, 0}
+	return &ParseResult{nil, nil, NewWrongNode(), 0, 0, 0}
 }
 
 func (self *ParseResult) Register(res *ParseResult) Node {
@@ -23,6 +24,7 @@ func (self *ParseResult) Register(res *ParseResult) Node {
 
 func (self *ParseResult) TryRegister(res *ParseResult) Node {
 	if res.error != nil {
+		self.tryError = res.error
 		self.reverseCount = res.advanceCount
 		return nil
 	}
@@ -1,4 +1,4 @@
-package main
+package interp
 
 import (
 	"fmt"
@@ -34,9 +34,19 @@ func (self *Parser) Reverse(amount int) *Token {
 	return self.current
 }
 
+func (self *Parser) SkipNewlines(res *ParseResult) {
+	for self.current.tokenType == NEWLINE {
+		res.RegisterAdvancement()
+		self.Advance()
+	}
+}
+
 func (self *Parser) Parse() *ParseResult {
 	res := self.Statements()
 	if res.error == nil && self.current.tokenType != EOF {
+		if res.tryError != nil {
+			return res.Failure(res.tryError)
+		}
 		return res.Failure(NewInvalidSyntaxError(
 			"Expected '+', '-', '*', '/', '^', '==', '!=', '<', '>', '<=', '>=', '&&', or '||'",
 			self.current.start, self.current.end,
@@ -57,9 +67,20 @@ func (self *Parser) FunctionDefinition() *ParseResult {
 	res.RegisterAdvancement()
 	self.Advance()
 
+	var name *Token
+	if self.current.tokenType == IDENTIFIER {
+		name = self.current
+		res.RegisterAdvancement()
+		self.Advance()
+	}
+
 	if self.current.tokenType != LPAREN {
+		message := "Expected '('"
+		if name == nil {
+			message = "Expected identifier, or '('"
+		}
 		return res.Failure(NewInvalidSyntaxError(
-			"Expected '('", self.current.start, self.current.end,
+			message, self.current.start, self.current.end,
 		))
 	}
 
@@ -67,36 +88,63 @@ func (self *Parser) FunctionDefinition() *ParseResult {
 	self.Advance()
 
 	arguments := make([]*Token, 0)
+	var defaults []Node
+	var rest *Token
 
 	if self.current.tokenType == IDENTIFIER {
-		arguments = append(arguments, self.current)
-
-		res.RegisterAdvancement()
-		self.Advance()
+		for {
+			argument := self.current
 
-		for self.current.tokenType == COMMA {
 			res.RegisterAdvancement()
 			self.Advance()
 
-			if self.current.tokenType != IDENTIFIER {
+			if self.current.tokenType == ELLIPSIS {
+				rest = argument
+				res.RegisterAdvancement()
+				self.Advance()
+				break
+			}
+
+			if self.current.tokenType == EQ {
+				res.RegisterAdvancement()
+				self.Advance()
+
+				value := res.Register(self.Expression())
+				if res.error != nil {
+					return res
+				}
+				defaults = append(defaults, value)
+			} else if len(defaults) > 0 {
 				return res.Failure(NewInvalidSyntaxError(
-					"Expected identifier", self.current.start, self.current.end,
+					"Expected '='", self.current.start, self.current.end,
 				))
 			}
 
-			arguments = append(arguments, self.current)
+			arguments = append(arguments, argument)
+
+			if self.current.tokenType != COMMA {
+				break
+			}
 
 			res.RegisterAdvancement()
 			self.Advance()
+
+			if self.current.tokenType != IDENTIFIER {
+				return res.Failure(NewInvalidSyntaxError(
+					"Expected identifier", self.current.start, self.current.end,
+				))
+			}
 		}
 	}
 
 	if self.current.tokenType != RPAREN {
 		var message string
-		if len(arguments) == 0 {
+		if len(arguments) == 0 && rest == nil {
 			message = "Expected identifier, or ')'"
+		} else if rest != nil {
+			message = "Expected ')'"
 		} else {
-			message = "Expected ',', or ')'"
+			message = "Expected ',', '=', '...', or ')'"
 		}
 		return res.Failure(NewInvalidSyntaxError(
 			message, self.current.start, self.current.end,
@@ -139,7 +187,7 @@ func (self *Parser) FunctionDefinition() *ParseResult {
 	res.RegisterAdvancement()
 	self.Advance()
 
-	return res.Success(NewFunctionDefinitionNode(arguments, body))
+	return res.Success(NewFunctionDefinitionNode(name, arguments, defaults, rest, body))
 }
 
 func (self *Parser) WhileExpression() *ParseResult {
@@ -218,9 +266,13 @@ func (self *Parser) ForExpression() *ParseResult {
 	res.RegisterAdvancement()
 	self.Advance()
 
-	if !self.current.Matches(KEYWORD, "from") {
+	if self.current.tokenType == COMMA || self.current.Matches(IDENTIFIER, "in") {
+		return self.ForEachExpression(res, varname)
+	}
+
+	if !self.current.Matches(IDENTIFIER, "from") {
 		return res.Failure(NewInvalidSyntaxError(
-			"Expected 'from'", self.current.start, self.current.end,
+			"Expected 'from', 'in', or ','", self.current.start, self.current.end,
 		))
 	}
 
@@ -232,7 +284,7 @@ func (self *Parser) ForExpression() *ParseResult {
 		return res
 	}
 
-	if !self.current.Matches(KEYWORD, "to") {
+	if !self.current.Matches(IDENTIFIER, "to") {
 		return res.Failure(NewInvalidSyntaxError(
 			"Expected 'to'", self.current.start, self.current.end,
 		))
@@ -247,7 +299,7 @@ func (self *Parser) ForExpression() *ParseResult {
 	}
 
 	var step Node
-	if self.current.Matches(KEYWORD, "step") {
+	if self.current.Matches(IDENTIFIER, "step") {
 		res.RegisterAdvancement()
 		self.Advance()
 
@@ -300,6 +352,76 @@ func (self *Parser) ForExpression() *ParseResult {
 	return res.Success(NewForNode(varname, from, to, step, body))
 }
 
+func (self *Parser) ForEachExpression(res *ParseResult, varname *Token) *ParseResult {
+	var keyname *Token
+	if self.current.tokenType == COMMA {
+		res.RegisterAdvancement()
+		self.Advance()
+
+		if self.current.tokenType != IDENTIFIER {
+			return res.Failure(NewInvalidSyntaxError(
+				"Expected identifier", self.current.start, self.current.end,
+			))
+		}
+
+		keyname = varname
+		varname = self.current
+
+		res.RegisterAdvancement()
+		self.Advance()
+	}
+
+	if !self.current.Matches(IDENTIFIER, "in") {
+		return res.Failure(NewInvalidSyntaxError(
+			"Expected 'in'", self.current.start, self.current.end,
+		))
+	}
+
+	res.RegisterAdvancement()
+	self.Advance()
+
+	iterable := res.Register(self.Expression())
+	if res.error != nil {
+		return res
+	}
+
+	if !self.current.Matches(KEYWORD, "do") {
+		return res.Failure(NewInvalidSyntaxError(
+			"Expected 'do'", self.current.start, self.current.end,
+		))
+	}
+
+	res.RegisterAdvancement()
+	self.Advance()
+
+	var body Node
+	if self.current.tokenType == NEWLINE {
+		res.RegisterAdvancement()
+		self.Advance()
+
+		body = res.Register(self.Statements())
+		if res.error != nil {
+			return res
+		}
+	} else {
+		body = res.Register(self.Statement())
+		if res.error != nil {
+			return res
+		}
+	}
+
+	if !self.current.Matches(KEYWORD, "end") {
+		return res.Failure(NewInvalidSyntaxError(
+			"Expected 'end'", self.current.start, self.current.end,
+		))
+	}
+
+	res.RegisterAdvancement()
+	self.Advance()
+
+	return res.Success(NewForEachNode(keyname, varname, iterable, body))
+}
+
 func (self *Parser) ListExpression() *ParseResult {
 	res := NewParseResult()
 	start := self.current.start.Copy()
@@ -312,6 +434,7 @@ func (self *Parser) ListExpression() *ParseResult {
 
 	res.RegisterAdvancement()
 	self.Advance()
+	self.SkipNewlines(res)
 
 	var values []Node
 
@@ -323,10 +446,12 @@ func (self *Parser) ListExpression() *ParseResult {
 		if res.error != nil {
 			return res
 		}
+		self.SkipNewlines(res)
 
 		for self.current.tokenType == COMMA {
 			res.RegisterAdvancement()
 			self.Advance()
+			self.SkipNewlines(res)
 
 			values = append(values, res.Register(self.Expression()))
 			if res.error != nil {
@@ -335,6 +460,7 @@ func (self *Parser) ListExpression() *ParseResult {
 						"identifier, '+', '-', '(', or '!'", self.current.start, self.current.end,
 				))
 			}
+			self.SkipNewlines(res)
 		}
 
 		if self.current.tokenType != RBRACKET {
@@ -350,6 +476,75 @@ func (self *Parser) ListExpression() *ParseResult {
 	return res.Success(NewListNode(values, start, self.current.end.Copy(), false))
 }
 
+func (self *Parser) MapExpression() *ParseResult {
+	res := NewParseResult()
+	start := self.current.start.Copy()
+
+	if self.current.tokenType != LBRACE {
+		return res.Failure(NewInvalidSyntaxError(
+			"Expected '{'", self.current.start, self.current.end,
+		))
+	}
+
+	res.RegisterAdvancement()
+	self.Advance()
+	self.SkipNewlines(res)
+
+	var keys, values []Node
+
+	if self.current.tokenType == RBRACE {
+		res.RegisterAdvancement()
+		self.Advance()
+		return res.Success(NewMapNode(keys, values, start, self.current.end.Copy()))
+	}
+
+	for {
+		key := res.Register(self.Expression())
+		if res.error != nil {
+			return res
+		}
+		self.SkipNewlines(res)
+
+		if self.current.tokenType != COLON {
+			return res.Failure(NewInvalidSyntaxError(
+				"Expected ':'", self.current.start, self.current.end,
+			))
+		}
+
+		res.RegisterAdvancement()
+		self.Advance()
+		self.SkipNewlines(res)
+
+		value := res.Register(self.Expression())
+		if res.error != nil {
+			return res
+		}
+		self.SkipNewlines(res)
+
+		keys = append(keys, key)
+		values = append(values, value)
+
+		if self.current.tokenType != COMMA {
+			break
+		}
+
+		res.RegisterAdvancement()
+		self.Advance()
+		self.SkipNewlines(res)
+	}
+
+	if self.current.tokenType != RBRACE {
+		return res.Failure(NewInvalidSyntaxError(
+			"Expected ',', or '}'", self.current.start, self.current.end,
+		))
+	}
+
+	res.RegisterAdvancement()
+	self.Advance()
+
+	return res.Success(NewMapNode(keys, values, start, self.current.end.Copy()))
+}
+
 func (self *Parser) IfExpression() *ParseResult {
 	res := NewParseResult()
 	allCases := res.Register(self.IfElseifExpression("if"))
@@ -433,11 +628,13 @@ func (self *Parser) Atom() *ParseResult {
 	} else if self.current.tokenType == LPAREN {
 		res.RegisterAdvancement()
 		self.Advance()
+		self.SkipNewlines(res)
 
 		expression := res.Register(self.Expression())
 		if res.error != nil {
 			return res
 		}
+		self.SkipNewlines(res)
 
 		if self.current.tokenType != RPAREN {
 			return res.Failure(NewInvalidSyntaxError(
@@ -460,10 +657,12 @@ func (self *Parser) Atom() *ParseResult {
 		return self.FunctionDefinition()
 	} else if self.current.tokenType == LBRACKET {
 		return self.ListExpression()
+	} else if self.current.tokenType == LBRACE {
+		return self.MapExpression()
 	}
 
 	return res.Failure(NewInvalidSyntaxError(
-		"Expected 'var', number, identifier, '+', '-', '[', '(', "+
+		"Expected 'var', number, identifier, '+', '-', '[', '{', '(', "+
 			"'if', 'for', 'while', or 'function'",
 		self.current.start, self.current.end,
 	))
@@ -472,54 +671,156 @@ func (self *Parser) Atom() *ParseResult {
 func (self *Parser) Call() *ParseResult {
 	res := NewParseResult()
 
-	atom := res.Register(self.Atom())
+	node := res.Register(self.Atom())
 	if res.error != nil {
 		return res
 	}
 
-	if self.current.tokenType == LPAREN {
+	for {
+		switch self.current.tokenType {
+		case LPAREN:
+			node = res.Register(self.CallArguments(node))
+		case LBRACKET:
+			node = res.Register(self.Index(node))
+		default:
+			return res.Success(node)
+		}
+		if res.error != nil {
+			return res
+		}
+	}
+}
+
+func (self *Parser) Index(node Node) *ParseResult {
+	res := NewParseResult()
+
+	res.RegisterAdvancement()
+	self.Advance()
+	self.SkipNewlines(res)
+
+	var bounds []Node
+	for {
+		var bound Node
+		if self.current.tokenType != COLON && self.current.tokenType != RBRACKET {
+			bound = res.Register(self.Expression())
+			if res.error != nil {
+				return res
+			}
+			self.SkipNewlines(res)
+		}
+		bounds = append(bounds, bound)
+
+		if self.current.tokenType != COLON || len(bounds) == 3 {
+			break
+		}
 		res.RegisterAdvancement()
 		self.Advance()
+		self.SkipNewlines(res)
+	}
 
-		var arguments []Node
+	if self.current.tokenType != RBRACKET {
+		message := "Expected ':', or ']'"
+		if len(bounds) == 3 {
+			message = "Expected ']'"
+		}
+		return res.Failure(NewInvalidSyntaxError(
+			message, self.current.start, self.current.end,
+		))
+	}
 
-		if self.current.tokenType == RPAREN {
-			res.RegisterAdvancement()
-			self.Advance()
+	if len(bounds) == 1 && bounds[0] == nil {
+		return res.Failure(NewInvalidSyntaxError(
+			"Expected index, or ':'", self.current.start, self.current.end,
+		))
+	}
 
-		} else {
-			arguments = append(arguments, res.Register(self.Expression()))
-			if res.error != nil {
+	end := self.current.end.Copy()
+	res.RegisterAdvancement()
+	self.Advance()
+
+	if len(bounds) == 1 {
+		return res.Success(NewIndexNode(node, bounds[0], end))
+	}
+	for len(bounds) < 3 {
+		bounds = append(bounds, nil)
+	}
+	return res.Success(NewSliceNode(node, bounds[0], bounds[1], bounds[2], end))
+}
+
+func (self *Parser) CallArguments(node Node) *ParseResult {
+	res := NewParseResult()
+
+	res.RegisterAdvancement()
+	self.Advance()
+	self.SkipNewlines(res)
+
+	var arguments []Node
+	var keywords []*Token
+	var keywordValues []Node
+
+	for self.current.tokenType != RPAREN {
+		if len(arguments) > 0 || len(keywords) > 0 {
+			if self.current.tokenType != COMMA {
 				return res.Failure(NewInvalidSyntaxError(
-					"Expected ')', 'var', 'if', 'for', 'while', 'function', int, float, "+
-						"identifier, '+', '-', '(', or '!'", self.current.start, self.current.end,
+					"Expected ',', or ')'", self.current.start, self.current.end,
 				))
 			}
+			res.RegisterAdvancement()
+			self.Advance()
+			self.SkipNewlines(res)
+		}
 
-			for self.current.tokenType == COMMA {
-				res.RegisterAdvancement()
-				self.Advance()
-
-				arguments = append(arguments, res.Register(self.Expression()))
-				if res.error != nil {
-					return res
+		if self.current.tokenType == IDENTIFIER && self.tokens[self.index+1].tokenType == EQ {
+			keyword := self.current
+			for _, other := range keywords {
+				if other.value == keyword.value {
+					return res.Failure(NewInvalidSyntaxError(
+						fmt.Sprintf("Argument '%s' is given more than once", keyword.value),
+						keyword.start, keyword.end,
+					))
 				}
 			}
 
-			if self.current.tokenType != RPAREN {
+			res.RegisterAdvancement()
+			self.Advance()
+			res.RegisterAdvancement()
+			self.Advance()
+			self.SkipNewlines(res)
+
+			value := res.Register(self.Expression())
+			if res.error != nil {
+				return res
+			}
+			keywords = append(keywords, keyword)
+			keywordValues = append(keywordValues, value)
+
+		} else {
+			if len(keywords) > 0 {
 				return res.Failure(NewInvalidSyntaxError(
-					"Expected ',', or ')'", self.current.start, self.current.end,
+					"Expected keyword argument, positional arguments must come first",
+					self.current.start, self.current.end,
 				))
 			}
 
-			res.RegisterAdvancement()
-			self.Advance()
+			argument := res.Register(self.Expression())
+			if res.error != nil {
+				if len(arguments) == 0 {
+					return res.Failure(NewInvalidSyntaxError(
+						"Expected ')', 'var', 'if', 'for', 'while', 'function', int, float, "+
+							"identifier, '+', '-', '(', or '!'", self.current.start, self.current.end,
+					))
+				}
+				return res
+			}
+			arguments = append(arguments, argument)
 		}
-
-		return res.Success(NewFunctionCallNode(atom, arguments))
+		self.SkipNewlines(res)
 	}
 
-	return res.Success(atom)
+	res.RegisterAdvancement()
+	self.Advance()
+
+	return res.Success(NewFunctionCallNode(node, arguments, keywords, keywordValues))
 }
 
 func (self *Parser) Power() *ParseResult {
@@ -628,6 +929,30 @@ func (self *Parser) Expression() *ParseResult {
 		))
 	}
 
+	if target, ok := node.(*VariableAccessNode); ok && self.current.tokenType == EQ {
+		res.RegisterAdvancement()
+		self.Advance()
+
+		expression := res.Register(self.Expression())
+		if res.error != nil {
+			return res
+		}
+
+		return res.Success(NewVariableUpdateNode(target.name, expression))
+	}
+
+	if target, ok := node.(*IndexNode); ok && self.current.tokenType == EQ {
+		res.RegisterAdvancement()
+		self.Advance()
+
+		expression := res.Register(self.Expression())
+		if res.error != nil {
+			return res
+		}
+
+		return res.Success(NewIndexAssignmentNode(target, expression))
+	}
+
 	return res.Success(node)
 }
 
@@ -646,6 +971,32 @@ func (self *Parser) Statement() *ParseResult {
 		return res.Success(NewReturnNode(expression, start, self.current.start.Copy()))
 	}
 
+	if self.current.Matches(KEYWORD, "global") || self.current.Matches(KEYWORD, "nonlocal") {
+		keyword := self.current.value.(string)
+		res.RegisterAdvancement()
+		self.Advance()
+
+		var names []*Token
+		for {
+			if self.current.tokenType != IDENTIFIER {
+				return res.Failure(NewInvalidSyntaxError(
+					"Expected identifier", self.current.start, self.current.end,
+				))
+			}
+			names = append(names, self.current)
+			res.RegisterAdvancement()
+			self.Advance()
+
+			if self.current.tokenType != COMMA {
+				break
+			}
+			res.RegisterAdvancement()
+			self.Advance()
+		}
+
+		return res.Success(NewScopeNode(keyword, names, start, self.current.start.Copy()))
+	}
+
 	if self.current.Matches(KEYWORD, "continue") {
 		res.RegisterAdvancement()
 		self.Advance()
@@ -680,6 +1031,10 @@ func (self *Parser) Statements() *ParseResult {
 
 	var statements []Node
 
+	if self.current.tokenType == EOF {
+		return res.Success(NewListNode(statements, start, self.current.end.Copy(), true))
+	}
+
 	statements = append(statements, res.Register(self.Statement()))
 	if res.error != nil {
 		return res
@@ -727,7 +1082,9 @@ func (self *Parser) BinaryOperation(left_function, right_function func() *ParseR
 
 	for self.current.tokenType.In(operations) {
 		operation := self.current
+		res.RegisterAdvancement()
 		self.Advance()
+		self.SkipNewlines(res)
 		right := res.Register(right_function())
 		if res.error != nil {
 			return res
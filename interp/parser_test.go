@@ -0,0 +1,36 @@
+package interp
+
+import (
+	"testing"
+)
+
+func TestIncomplete(t *testing.T) {
+	tests := []struct {
+		text       string
+		incomplete bool
+	}{
+		{"var f = function(x) do\n", true},
+		{"if TRUE do\n1\n", true},
+		{"[1,\n", true},
+		{"{\"a\":\n", true},
+		{"f(1,\n", true},
+		{"(1 +\n", true},
+		{"var x = (\n", true},
+		{"1 *\n", true},
+		{"\"abc", true},
+		{"1 + )", false},
+		{"var = 1", false},
+		{"[1 2]", false},
+	}
+
+	for _, test := range tests {
+		_, err := Parse("<test>", test.text)
+		if err == nil {
+			t.Errorf("%q: expected a syntax error", test.text)
+			continue
+		}
+		if incomplete := err.(*Error).Incomplete(); incomplete != test.incomplete {
+			t.Errorf("%q: Incomplete() = %t, want %t", test.text, incomplete, test.incomplete)
+		}
+	}
+}
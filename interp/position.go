@@ -1,4 +1,4 @@
-package main
+package interp
 
 type Position struct {
 	index        int
@@ -22,3 +22,15 @@ func (self *Position) Advance(current rune) {
 func (self *Position) Copy() *Position {
 	return &Position{self.index, self.line, self.column, self.name, self.text}
 }
+
+func (self *Position) File() string {
+	return self.name
+}
+
+func (self *Position) Line() int {
+	return self.line + 1
+}
+
+func (self *Position) Column() int {
+	return self.column + 1
+}
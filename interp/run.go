@@ -0,0 +1,231 @@
+package interp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+type Options struct {
+	Args          []string
+	Globals       map[string]Value
+	Capabilities  Capability
+	MaxSteps      int
+	MaxAllocation int
+	MaxDepth      int
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type Interpreter struct {
+	options     Options
+	table       *SymbolTable
+	ctx         context.Context
+	interrupted int32
+	steps       int
+	allocated   int
+	depth       int
+
+	stdin  *bufio.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func NewInterpreter(options Options) *Interpreter {
+	res := &Interpreter{
+		options, NewSymbolTable(nil), context.Background(), 0, 0, 0, 0,
+		bufio.NewReader(os.Stdin), os.Stdout, os.Stderr,
+	}
+	if options.Stdin != nil {
+		res.stdin = bufio.NewReader(options.Stdin)
+	}
+	if options.Stdout != nil {
+		res.stdout = options.Stdout
+	}
+	if options.Stderr != nil {
+		res.stderr = options.Stderr
+	}
+
+	context := res.newContext("<program>")
+
+	for name, number := range Numbers {
+		res.table.Set(name, number.Copy().SetContext(context))
+	}
+
+	for name, function := range builtinFunctions {
+		res.table.Set(name, function.Copy().SetContext(context))
+	}
+
+	args := make([]Value, len(options.Args))
+	for i, arg := range options.Args {
+		args[i] = NewString(arg).SetContext(context)
+	}
+	res.table.Set("ARGS", NewList(args).SetContext(context))
+
+	for name, value := range options.Globals {
+		res.table.Set(name, value.Copy().SetContext(context))
+	}
+
+	return res
+}
+
+func (self *Interpreter) newContext(name string) *Context {
+	context := NewContext(name, nil, nil)
+	context.table = self.table
+	context.interpreter = self
+	return context
+}
+
+func (self *Interpreter) reset(ctx context.Context) {
+	self.ctx = ctx
+	atomic.StoreInt32(&self.interrupted, 0)
+	self.steps = 0
+	self.allocated = 0
+	self.depth = 0
+}
+
+func (self *Interpreter) Interrupt() {
+	atomic.StoreInt32(&self.interrupted, 1)
+}
+
+func (self *Interpreter) readLine() string {
+	line, _ := self.stdin.ReadString('\n')
+	return strings.TrimRight(line, "\r\n")
+}
+
+func (self *Interpreter) Get(name string) Value {
+	return self.table.Get(name)
+}
+
+func (self *Interpreter) Set(name string, value Value) {
+	self.table.Set(name, value.SetContext(self.newContext("<program>")))
+}
+
+func (self *Interpreter) Names() []string {
+	return self.table.Names()
+}
+
+func (self *Interpreter) Steps() int {
+	return self.steps
+}
+
+func (self *Interpreter) Run(name, text string) (Value, error) {
+	return self.RunContext(context.Background(), name, text)
+}
+
+func (self *Interpreter) EvalContext(ctx context.Context, source string) (Value, error) {
+	return self.RunContext(ctx, "<eval>", source)
+}
+
+func (self *Interpreter) RunContext(ctx context.Context, name, text string) (Value, error) {
+	self.reset(ctx)
+	return self.result(self.run(name, text))
+}
+
+func (self *Interpreter) Call(name string, args ...interface{}) (interface{}, error) {
+	return self.CallContext(context.Background(), name, args...)
+}
+
+func (self *Interpreter) CallContext(ctx context.Context, name string,
+	args ...interface{}) (interface{}, error) {
+
+	value := self.table.Get(name)
+	function, ok := value.(BaseFunction)
+	if !ok {
+		if value == nil {
+			return nil, fmt.Errorf("'%s' is not defined", name)
+		}
+		return nil, fmt.Errorf("'%s' is a %s, not a function", name, TypeName(value))
+	}
+
+	arguments := make([]Value, len(args))
+	for i, arg := range args {
+		argument, err := FromGo(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %s", i+1, err)
+		}
+		arguments[i] = argument
+	}
+
+	start := NewPosition(0, 0, 0, "<host>", name)
+	end := NewPosition(len(name), 0, len(name), "<host>", name)
+	self.reset(ctx)
+	function = function.Copy().SetPosition(start, end).SetContext(self.newContext("<host>")).(BaseFunction)
+
+	value, err := self.result(function.Execute(arguments, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := ToGo(value)
+	if err != nil {
+		return nil, fmt.Errorf("return value: %s", err)
+	}
+	return converted, nil
+}
+
+func (self *Interpreter) result(result *RuntimeResult) (Value, error) {
+	if result.error != nil {
+		return nil, result.error
+	}
+	if result.shouldExit {
+		return nil, &ExitError{result.exitCode}
+	}
+	return result.value, nil
+}
+
+func (self *Interpreter) run(name, text string) *RuntimeResult {
+	res := NewRuntimeResult()
+
+	node, err := parse(name, text)
+	if err != nil {
+		return res.Failure(err)
+	}
+	if program, ok := node.(*ListNode); ok && len(program.values) == 0 {
+		return res.Success(nil)
+	}
+
+	context := self.newContext("<program>")
+	value := res.Register(node.Interpret(context))
+	if res.error != nil || res.shouldExit {
+		return res
+	}
+
+	context.table.Set("ans", clone(value))
+	return res.Success(value)
+}
+
+func Tokenize(name, text string) ([]*Token, error) {
+	tokens, err := NewLexer(name, text).MakeTokens()
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func Parse(name, text string) (Node, error) {
+	node, err := parse(name, text)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func parse(name, text string) (Node, *Error) {
+	tokens, err := NewLexer(name, text).MakeTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	syntaxTree := NewParser(tokens).Parse()
+	if syntaxTree.error != nil {
+		return nil, syntaxTree.error
+	}
+	return syntaxTree.node, nil
+}
@@ -0,0 +1,296 @@
+package interp
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func runScript(t *testing.T, options Options, text string) (Value, error) {
+	t.Helper()
+	return NewInterpreter(options).Run("<test>", text)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"arithmetic", "1 + 2 * 3 ^ 2 - 4 / 8", "18.5"},
+		{"modulo", "7 % 3", "1"},
+		{"string concatenation", `"ab" + "cd"`, "abcd"},
+		{"string repetition", `"ab" * 3`, "ababab"},
+		{"comparison", "1 < 2 && 2 >= 2 && !(1 == 2)", "1"},
+		{"variables", "var x = 4\nvar y = x * 2\ny", "8"},
+		{"if", "if 1 > 2 do 1 elseif 2 > 1 do 2 else do 3 end", "2"},
+		{"for", "for i from 0 to 3 do i end", "[0, 1, 2]"},
+		{"for with step", "for i from 6 to 0 step -2 do i end", "[6, 4, 2]"},
+		{"while", "var i = 0\nwhile i < 3 do i = i + 1 end\ni", "3"},
+		{"break and continue", "for i from 0 to 10 do\nif i == 1 do continue end\nif i == 4 do break end\ni\nend", "[0, 2, 3]"},
+		{"function", "var f = function(a, b) do a * b end\nf(3, 4)", "12"},
+		{"return", "var f = function(x) do\nif x > 0 do return 1 end\nreturn 2\nend\n[f(1), f(-1)]", "[1, 2]"},
+		{"list append", "[1, 2] + 3", "[1, 2, 3]"},
+		{"list concatenation", "[1] * [2, 3]", "[1, 2, 3]"},
+		{"list remove", "[1, 2, 3] - 0", "[2, 3]"},
+		{"builtin len", `[len([1, 2]), len("abc"), len({"a": 1})]`, "[2, 3, 1]"},
+		{"ans", "1 + 1", "2"},
+		{"newlines in parentheses", "var x = (\n1 + 2\n)\nx", "3"},
+		{"newline after an operator", "var x = 1 +\n2 *\n3\nx == 7 &&\nx", "1"},
+
+		{"map literal", `{"a": 1, 2: "b"}`, "{a: 1, 2: b}"},
+		{"map keeps insertion order", `{"b": 1, "a": 2, "b": 3}`, "{b: 3, a: 2}"},
+		{"map merge", `{"a": 1} + {"b": 2, "a": 3}`, "{a: 3, b: 2}"},
+		{"map delete", `{"a": 1, "b": 2} - "a"`, "{b: 2}"},
+		{"map keys", `keys({"a": 1, "b": 2})`, "[a, b]"},
+		{"map equality", `[{"a": 1} == {"a": 1}, {"a": 1} == {"a": 2}]`, "[1, 0]"},
+
+		{"list index", "[1, 2, 3][1]", "2"},
+		{"negative index", "[1, 2, 3][-1]", "3"},
+		{"string index", `"abc"[2]`, "c"},
+		{"string index by character", `["héllo"[1], "héllo"[-1], len("héllo")]`, "[é, o, 5]"},
+		{"map index", `{"a": {"b": 5}}["a"]["b"]`, "5"},
+		{"index assignment", "var a = [1, 2]\na[0] = 9\na", "[9, 2]"},
+		{"nested index assignment", "var g = [[0, 0], [0, 0]]\ng[1][0] = 1\ng", "[[0, 0], [1, 0]]"},
+		{"assignment copies a list", "var a = [[1], 2]\nvar b = a\nb[0][0] = 9\nb[1] = 9\n[a, b]", "[[[1], 2], [[9], 9]]"},
+		{"assignment copies a map", `var a = {"x": {"y": 1}}` + "\nvar b = a\n" + `b["x"]["y"] = 2` + "\n[a, b]", "[{x: {y: 1}}, {x: {y: 2}}]"},
+		{"arguments are copies", "var a = [1, [2]]\nvar f = function(l) do\nl[0] = 100\nl[1][0] = 200\nl\nend\n[f(a), a]", "[[100, [200]], [1, [2]]]"},
+		{"call results are copies", "var l = [[1]]\nvar f = function() do l end\nf()[0][0] = 5\nl", "[[1]]"},
+		{"map index assignment", `var m = {}` + "\n" + `m["x"] = 1` + "\n" + `m["x"] = 2` + "\nm", "{x: 2}"},
+
+		{"slice", "[0, 1, 2, 3, 4][1:3]", "[1, 2]"},
+		{"slice with step", "[0, 1, 2, 3, 4][::2]", "[0, 2, 4]"},
+		{"reverse slice", `"abc"[::-1]`, "cba"},
+		{"string slice by character", `"héllo"[1:3] + "日本"[::-1]`, "él本日"},
+		{"slice out of range", "[0, 1][-10:10]", "[0, 1]"},
+
+		{"closure", "var make = function(n) do function(x) do x + n end end\nvar add = make(2)\nvar n = 100\nadd(1)", "3"},
+		{"closure counter", "var counter = function() do\nvar n = 0\nfunction() do\nnonlocal n\nn = n + 1\nend\nend\nvar c = counter()\nc()\nc()\nc()", "3"},
+		{"global", "var x = 1\nvar f = function() do\nglobal x\nx = 5\nend\nf()\nx", "5"},
+		{"global at the top level", "global q\nq = 1\nq", "1"},
+		{"update outer variable", "var x = 1\nif TRUE do x = 2 end\nx", "2"},
+
+		{"named function", "function double(x) do x * 2 end\ndouble(4)", "8"},
+		{"hoisting", "var r = even(10)\nfunction even(n) do\nif n == 0 do return 1 end\nodd(n - 1)\nend\nfunction odd(n) do\nif n == 0 do return 0 end\neven(n - 1)\nend\nr", "1"},
+		{"recursion", "function fact(n) do\nif n <= 1 do return 1 end\nn * fact(n - 1)\nend\nfact(5)", "120"},
+		{"function string", "function f() do 0 end\nf", "<function f>"},
+
+		{"default parameter", "function f(a, b = 10) do a + b end\n[f(1), f(1, 2)]", "[11, 3]"},
+		{"default from an earlier statement", "var d = 5\nfunction f(x = d) do x end\nf()", "5"},
+		{"hoisted function with defaults", "var r = f(1, 1)\nfunction f(x, y = 2) do x + y end\n[r, f(1)]", "[2, 3]"},
+		{"rest parameter", "function f(a, rest...) do rest end\nf(1, 2, 3)", "[2, 3]"},
+		{"keyword argument", "function f(a, b = 1, c = 2) do [a, b, c] end\nf(0, c = 5)", "[0, 1, 5]"},
+		{"contextual keywords as names", "function f(from, to, step = 1) do to - from + step end\nf(1, 5)", "5"},
+
+		{"for each", "for x in [1, 2, 3] do x * 2 end", "[2, 4, 6]"},
+		{"for each with index", `for i, c in "ab" do [i, c] end`, "[[0, a], [1, b]]"},
+		{"for each over characters", `for i, c in "hé!" do [i, c] end`, "[[0, h], [1, é], [2, !]]"},
+		{"for each over map", `for k, v in {"a": 1, "b": 2} do [k, v] end`, "[[a, 1], [b, 2]]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := runScript(t, Options{}, test.text)
+			if err != nil {
+				t.Fatalf("unexpected error:\n%s", err)
+			}
+			if value == nil {
+				t.Fatalf("got nothing, want %s", test.want)
+			}
+			if value.String() != test.want {
+				t.Errorf("got %s, want %s", value, test.want)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name, text, errorName, details string
+	}{
+		{"undefined variable", "x + 1", "RuntimeError", "'x' is not defined"},
+		{"illegal character", "1 $ 2", "IllegalCharacterError", "'$'"},
+		{"invalid syntax", "var = 1", "InvalidSyntaxError", "Expected identifier"},
+		{"too many arguments", "var f = function(a) do a end\nf(1, 2)", "RuntimeError", "1 too many arguments"},
+		{"too few arguments", "var f = function(a, b) do a end\nf(1)", "RuntimeError", "1 too few arguments"},
+		{"unknown keyword", "function f(a) do a end\nf(b = 1)", "RuntimeError", "Unknown argument 'b'"},
+		{"default before its definition", "var r = f()\nfunction f(x = 2) do x end", "RuntimeError", "Default value of 'x' is not defined yet"},
+		{"nothing as a keyword argument", "var a = 5\nfunction f(a = 1) do a end\n" + `f(a = print(""))`, "RuntimeError", "'a' is not defined"},
+		{"nothing as an argument", "var a = 5\nfunction f(a) do a end\n" + `f(print(""))`, "RuntimeError", "'a' is not defined"},
+		{"calling a number", "var x = 1\nx()", "RuntimeError", "A number can not be called"},
+		{"assignment to undeclared", "y = 1", "RuntimeError", "'y' is not defined"},
+		{"global without a value", "global q\nq", "RuntimeError", "'q' is not defined"},
+		{"nonlocal without enclosing", "var f = function() do\nnonlocal z\nend\nf()", "RuntimeError", "'z' is not defined in an enclosing scope"},
+		{"nothing as an operand", `1 + print("")`, "RuntimeError", "Can not use nothing in an operation"},
+		{"nothing as a left operand", `print("") * 2`, "RuntimeError", "Can not use nothing in an operation"},
+		{"negating nothing", `-print("")`, "RuntimeError", "Can not use nothing in an operation"},
+		{"index out of range", "[1, 2][2]", "RuntimeError", "Index out of range"},
+		{"fractional index", "[1, 2][0.5]", "RuntimeError", "Index must not be fractional"},
+		{"missing key", `{"a": 1}["b"]`, "RuntimeError", "Key 'b' not found"},
+		{"invalid key", `{[1]: 1}`, "RuntimeError", "Map keys must be numbers or strings"},
+		{"nothing as key", `{print(""): 1}`, "RuntimeError", "Map keys must be numbers or strings, not nothing"},
+		{"deleting nothing", `{1: 2} - print("")`, "RuntimeError", "Can not use nothing in an operation"},
+		{"looking up nothing", `{1: 2} / print("")`, "RuntimeError", "Can not use nothing in an operation"},
+		{"indexing a list with nothing", `[1, 2][print("")]`, "RuntimeError", "Can not index with nothing"},
+		{"indexing a string with nothing", `"ab"[print("")]`, "RuntimeError", "Can not index with nothing"},
+		{"indexing a map with nothing", `{1: 2}[print("")]`, "RuntimeError", "Can not index with nothing"},
+		{"assigning to nothing", `var m = {}` + "\n" + `m[print("")] = 3`, "RuntimeError", "Can not index with nothing"},
+		{"assigning to a list index nothing", `var l = [1]` + "\n" + `l[print("")] = 3`, "RuntimeError", "Can not index with nothing"},
+		{"index on number", "var x = 1\nx[0]", "RuntimeError", "'[]' not supported for number"},
+		{"slice step zero", "[1, 2][::0]", "RuntimeError", "Slice step must not be zero"},
+		{"loop over number", "for x in 5 do x end", "RuntimeError", "Can not loop over a number"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := runScript(t, Options{}, test.text)
+			if err == nil {
+				t.Fatalf("expected %s, got no error", test.errorName)
+			}
+			var scriptError *Error
+			if !errors.As(err, &scriptError) {
+				t.Fatalf("expected *Error, got %T: %s", err, err)
+			}
+			if scriptError.Name() != test.errorName {
+				t.Errorf("got %s, want %s", scriptError.Name(), test.errorName)
+			}
+			if !strings.Contains(scriptError.Details(), test.details) {
+				t.Errorf("details %q do not contain %q", scriptError.Details(), test.details)
+			}
+		})
+	}
+}
+
+func TestInterpretersAreIsolated(t *testing.T) {
+	first := NewInterpreter(Options{})
+	second := NewInterpreter(Options{})
+
+	if _, err := first.Run("<test>", "var x = 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Run("<test>", "x"); err == nil {
+		t.Error("variable leaked into another interpreter")
+	}
+	if first.Get("x") == nil {
+		t.Error("variable was not kept between runs")
+	}
+}
+
+func TestGlobals(t *testing.T) {
+	value, err := runScript(t, Options{
+		Globals: map[string]Value{"limit": NewNumber(3)},
+		Args:    []string{"a", "b"},
+	}, "[limit, ARGS]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.String() != "[3, [a, b]]" {
+		t.Errorf("got %s", value)
+	}
+}
+
+func TestStreams(t *testing.T) {
+	var stdout bytes.Buffer
+	_, err := runScript(t, Options{
+		Stdin:        strings.NewReader("gomeo\n42\n"),
+		Stdout:       &stdout,
+		Capabilities: CAPABILITY_STDIN,
+	}, `var name = input("")`+"\n"+`var n = inputNumber("")`+"\n"+`println("hello", name)`+"\n"+`print(n + 1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "hello gomeo\n43" {
+		t.Errorf("got %q", stdout.String())
+	}
+}
+
+func TestExit(t *testing.T) {
+	var stdout bytes.Buffer
+	_, err := runScript(t, Options{
+		Stdout:       &stdout,
+		Capabilities: CAPABILITY_EXIT,
+	}, "var f = function() do exit(3) end\nprint(1)\nf()\nprint(2)")
+
+	var exitError *ExitError
+	if !errors.As(err, &exitError) {
+		t.Fatalf("expected *ExitError, got %v", err)
+	}
+	if exitError.Code != 3 {
+		t.Errorf("got exit code %d, want 3", exitError.Code)
+	}
+	if stdout.String() != "1" {
+		t.Errorf("script was not stopped by exit, output %q", stdout.String())
+	}
+}
+
+func TestEmptyProgram(t *testing.T) {
+	interpreter := NewInterpreter(Options{})
+	if _, err := interpreter.Run("<test>", "1 + 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, text := range []string{"", "\n\n", "# comment", "#!/usr/bin/env gomeo\n# comment\n"} {
+		value, err := interpreter.Run("<test>", text)
+		if err != nil || value != nil {
+			t.Errorf("%q: got %v, %v, want nothing", text, value, err)
+		}
+	}
+
+	if ans := interpreter.Get("ans"); ans == nil || ans.String() != "2" {
+		t.Errorf("an empty program changed ans to %v", ans)
+	}
+}
+
+func TestShebang(t *testing.T) {
+	value, err := runScript(t, Options{}, "#!/usr/bin/env gomeo\n1 + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.String() != "2" {
+		t.Errorf("got %s", value)
+	}
+}
+
+func TestCall(t *testing.T) {
+	interpreter := NewInterpreter(Options{})
+	_, err := interpreter.Run("<test>", "var add = function(a, b) do a + b end\nvar join = function(values...) do values end\nvar n = 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := interpreter.Call("add", 1, 2.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != 3.5 {
+		t.Errorf("got %v, want 3.5", sum)
+	}
+
+	values, err := interpreter.Call("join", "a", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"a", 1.0}) {
+		t.Errorf("got %#v", values)
+	}
+
+	if _, err := interpreter.Call("missing"); err == nil || !strings.Contains(err.Error(), "not defined") {
+		t.Errorf("expected an error for an undefined function, got %v", err)
+	}
+	if _, err := interpreter.Call("n"); err == nil || !strings.Contains(err.Error(), "not a function") {
+		t.Errorf("expected an error for a number, got %v", err)
+	}
+	if _, err := interpreter.Call("add", 1); err == nil {
+		t.Error("expected an error for a missing argument")
+	}
+	if _, err := interpreter.Call("add", 1, make(chan int)); err == nil {
+		t.Error("expected an error for an unconvertible argument")
+	}
+	if _, err := interpreter.Call("join", func() {}); err == nil {
+		t.Error("expected an error for a function argument")
+	}
+	if _, err := interpreter.Call("join", interpreter.Get("add")); err == nil ||
+		!strings.HasPrefix(err.Error(), "return value: ") {
+		t.Errorf("expected an error for an unconvertible return value, got %v", err)
+	}
+}
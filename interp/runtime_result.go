@@ -1,4 +1,4 @@
-package main
+package interp
 
 type RuntimeResult struct {
 	value          Value
@@ -6,10 +6,12 @@ type RuntimeResult struct {
 	returnValue    Value
 	shouldContinue bool
 	shouldBreak    bool
+	shouldExit     bool
+	exitCode       int
 }
 
 func NewRuntimeResult() *RuntimeResult {
-	return &RuntimeResult{nil, nil, nil, false, false}
+	return &RuntimeResult{nil, nil, nil, false, false, false, 0}
 }
 
 func (self *RuntimeResult) Reset() {
@@ -18,6 +20,8 @@ func (self *RuntimeResult) Reset() {
 	self.returnValue = nil
 	self.shouldContinue = false
 	self.shouldBreak = false
+	self.shouldExit = false
+	self.exitCode = 0
 }
 
 func (self *RuntimeResult) Register(res *RuntimeResult) Value {
@@ -25,6 +29,8 @@ func (self *RuntimeResult) Register(res *RuntimeResult) Value {
 	self.returnValue = res.returnValue
 	self.shouldContinue = res.shouldContinue
 	self.shouldBreak = res.shouldBreak
+	self.shouldExit = res.shouldExit
+	self.exitCode = res.exitCode
 	return res.value
 }
 
@@ -52,6 +58,13 @@ func (self *RuntimeResult) SuccessBreak() *RuntimeResult {
 	return self
 }
 
+func (self *RuntimeResult) SuccessExit(code int) *RuntimeResult {
+	self.Reset()
+	self.shouldExit = true
+	self.exitCode = code
+	return self
+}
+
 func (self *RuntimeResult) Failure(err *Error) *RuntimeResult {
 	self.Reset()
 	self.error = err
@@ -59,5 +72,6 @@ func (self *RuntimeResult) Failure(err *Error) *RuntimeResult {
 }
 
 func (self *RuntimeResult) ShouldReturn() bool {
-	return self.error != nil || self.returnValue != nil || self.shouldContinue || self.shouldBreak
+	return self.error != nil || self.returnValue != nil || self.shouldContinue || self.shouldBreak ||
+		self.shouldExit
 }
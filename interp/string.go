@@ -1,4 +1,4 @@
-package main
+package interp
 
 import (
 	"math"
@@ -56,6 +56,12 @@ func (self *String) End() *Position {
 func (self *String) Add(value Value) (Value, *Error) {
 	switch v := value.(type) {
 	case *String:
+		err := self.context.allocate(
+			float64(len(self.value)+len(v.value)), self.Start(), value.End(),
+		)
+		if err != nil {
+			return nil, err
+		}
 		return NewString(self.value + v.value).SetContext(self.context), nil
 	default:
 		return nil, NewRuntimeError(
@@ -79,7 +85,17 @@ func (self *String) Multiply(value Value) (Value, *Error) {
 				self.Start(), value.End(), self.context,
 			)
 		}
-		return NewString(strings.Repeat(self.value, int(v.value))), nil
+		if v.value < 0 {
+			return nil, NewRuntimeError(
+				"'*' not supported for string and negative number",
+				self.Start(), value.End(), self.context,
+			)
+		}
+		err := self.context.allocate(float64(len(self.value))*v.value, self.Start(), value.End())
+		if err != nil {
+			return nil, err
+		}
+		return NewString(strings.Repeat(self.value, int(v.value))).SetContext(self.context), nil
 	default:
 		return nil, NewRuntimeError(
 			"'*' not supported for string and type", self.Start(), value.End(), self.context,
@@ -240,3 +256,35 @@ func (self *String) Not() (*Number, *Error) {
 	}
 	return NewNumber(res), nil
 }
+
+func (self *String) Index(index Value) (Value, *Error) {
+	runes := []rune(self.value)
+	i, err := toIndex(index, len(runes), self.context)
+	if err != nil {
+		return nil, err
+	}
+	return NewString(string(runes[i])).SetContext(self.context), nil
+}
+
+func (self *String) SetIndex(index, value Value) *Error {
+	return NewRuntimeError(
+		"Strings can not be changed", index.Start(), index.End(), self.context,
+	)
+}
+
+func (self *String) Slice(start, end, step Value) (Value, *Error) {
+	runes := []rune(self.value)
+	indices, err := sliceIndices(len(runes), start, end, step, self.context)
+	if err != nil {
+		return nil, err
+	}
+	if err := self.context.allocate(float64(len(indices)), self.Start(), self.End()); err != nil {
+		return nil, err
+	}
+
+	res := make([]rune, len(indices))
+	for i, index := range indices {
+		res[i] = runes[index]
+	}
+	return NewString(string(res)).SetContext(self.context), nil
+}
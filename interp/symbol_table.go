@@ -0,0 +1,103 @@
+package interp
+
+import (
+	"sort"
+)
+
+type SymbolTable struct {
+	symbols   map[string]Value
+	redirects map[string]*SymbolTable
+	parent    *SymbolTable
+}
+
+func NewSymbolTable(parent *SymbolTable) *SymbolTable {
+	return &SymbolTable{make(map[string]Value), make(map[string]*SymbolTable), parent}
+}
+
+func (self *SymbolTable) Get(name string) Value {
+	if target, ok := self.redirects[name]; ok {
+		return target.Get(name)
+	}
+	value, ok := self.symbols[name]
+	if !ok && self.parent != nil {
+		return self.parent.Get(name)
+	}
+	return value
+}
+
+func (self *SymbolTable) Set(name string, value Value) {
+	if target, ok := self.redirects[name]; ok {
+		target.Set(name, value)
+		return
+	}
+	self.symbols[name] = value
+}
+
+func (self *SymbolTable) Update(name string, value Value) bool {
+	table := self.Lookup(name)
+	if table == nil {
+		return false
+	}
+	table.symbols[name] = value
+	return true
+}
+
+func (self *SymbolTable) Lookup(name string) *SymbolTable {
+	if target, ok := self.redirects[name]; ok {
+		return target
+	}
+	if _, ok := self.symbols[name]; ok {
+		return self
+	}
+	if self.parent != nil {
+		return self.parent.Lookup(name)
+	}
+	return nil
+}
+
+func (self *SymbolTable) Global(name string) {
+	root := self
+	for root.parent != nil {
+		root = root.parent
+	}
+	if root != self {
+		self.redirects[name] = root
+	} else if _, ok := self.symbols[name]; !ok {
+		// At the top level the name is declared without a value, so it can be assigned later.
+		self.symbols[name] = nil
+	}
+}
+
+func (self *SymbolTable) Nonlocal(name string) bool {
+	if self.parent == nil {
+		return false
+	}
+	table := self.parent.Lookup(name)
+	if table == nil {
+		return false
+	}
+	self.redirects[name] = table
+	return true
+}
+
+func (self *SymbolTable) Remove(name string) {
+	delete(self.symbols, name)
+}
+
+func (self *SymbolTable) Names() []string {
+	seen := make(map[string]bool)
+	for table := self; table != nil; table = table.parent {
+		for name, value := range table.symbols {
+			if value != nil {
+				seen[name] = true
+			}
+		}
+	}
+
+	res := make([]string, 0, len(seen))
+	for name := range seen {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
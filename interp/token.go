@@ -1,4 +1,4 @@
-package main
+package interp
 
 import (
 	"fmt"
@@ -23,6 +23,9 @@ const (
 	LBRACKET TokenType = "LBRACKET"
 	RBRACKET TokenType = "RBRACKET"
 
+	LBRACE TokenType = "LBRACE"
+	RBRACE TokenType = "RBRACE"
+
 	KEYWORD    TokenType = "KEYWORD"
 	IDENTIFIER TokenType = "IDENTIFIER"
 	EQ         TokenType = "EQ"
@@ -38,7 +41,9 @@ const (
 	AND TokenType = "AND"
 	OR  TokenType = "OR"
 
-	COMMA TokenType = "COMMA"
+	COMMA    TokenType = "COMMA"
+	COLON    TokenType = "COLON"
+	ELLIPSIS TokenType = "ELLIPSIS"
 
 	NEWLINE TokenType = "NEWLINE"
 	EOF     TokenType = "EOF"
@@ -48,11 +53,17 @@ func KEYWORDS() []string {
 	return []string{
 		"var",
 		"if", "do", "elseif", "else", "end",
-		"while", "for", "from", "to", "step", "continue", "break",
+		"while", "for", "continue", "break",
 		"function", "return",
+		"global", "nonlocal",
 	}
 }
 
+// Words that only have a meaning inside a for loop header, and can be used as names elsewhere.
+func CONTEXTUAL_KEYWORDS() []string {
+	return []string{"from", "to", "step", "in"}
+}
+
 func InKeywords(s string) bool {
 	for _, word := range KEYWORDS() {
 		if s == word {
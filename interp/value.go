@@ -1,4 +1,4 @@
-package main
+package interp
 
 import (
 	"fmt"
@@ -33,3 +33,14 @@ type Value interface {
 
 	IsTrue() bool
 }
+
+type Indexable interface {
+	Value
+	Index(index Value) (Value, *Error)
+	SetIndex(index, value Value) *Error
+}
+
+type Sliceable interface {
+	Value
+	Slice(start, end, step Value) (Value, *Error)
+}